@@ -0,0 +1,187 @@
+package httprouter
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+type AccessLogFormat int
+
+const (
+	// AccessLogStructured emits one slog record per request.
+	AccessLogStructured AccessLogFormat = iota
+	// AccessLogCommon writes one line per request in the Common Log Format.
+	AccessLogCommon
+	// AccessLogCombined writes one line per request in the Combined Log Format.
+	AccessLogCombined
+)
+
+const commonLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+type AccessLogConfig struct {
+	// Logger receives structured records, slog.Default() is used when it is nil.
+	Logger *slog.Logger
+	// Message of structured records, "http request" is used when it is empty.
+	Message string
+	// Level returns the level of a structured record by the response status,
+	// when it is nil 5xx are logged as errors, 4xx as warnings and the rest as info.
+	Level func(status int) slog.Level
+	// Format selects structured records or Common/Combined Log Format lines written to Output.
+	Format AccessLogFormat
+	// Output receives Common/Combined Log Format lines, os.Stdout is used when it is nil.
+	Output io.Writer
+	// SkipPaths are request paths that are never logged, e.g. health checks.
+	SkipPaths []string
+	// Sample decides whether a request is logged once the response is known, every request is logged when it is nil.
+	Sample func(request *http.Request, status int) bool
+}
+
+// SampleRatio logs the ratio (from 0 to 1) of requests answered with status below 500, and every request
+// answered with 5xx.
+func SampleRatio(ratio float64) func(request *http.Request, status int) bool {
+	return func(_ *http.Request, status int) bool {
+		return status >= http.StatusInternalServerError || rand.Float64() < ratio //nolint:gosec
+	}
+}
+
+// AccessLog logs every request with its method, route, params, status, size, duration, remote IP, user agent,
+// request ID and the error returned by the next handler. The status of a request whose handler returned an error without
+// writing a response is the one DefaultErrorHandler answers with. Add it with Router.Pre to log unmatched requests
+// as well.
+func AccessLog(config AccessLogConfig) MiddlewareFunc {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	if config.Message == "" {
+		config.Message = "http request"
+	}
+
+	if config.Level == nil {
+		config.Level = accessLogLevel
+	}
+
+	if config.Output == nil {
+		config.Output = os.Stdout
+	}
+
+	skipPaths := make(map[string]struct{}, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skipPaths[path] = struct{}{}
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if _, ok := skipPaths[request.URL.Path]; ok {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			start := time.Now()
+			recorder := newResponseRecorder(responseWriter)
+
+			err := next.Handle(recorder, request)
+
+			status := recorder.statusFor(err)
+			if config.Sample != nil && !config.Sample(request, status) {
+				return err //nolint:wrapcheck
+			}
+
+			if config.Format == AccessLogStructured {
+				logAccessRecord(config, request, recorder.bytes, status, time.Since(start), err)
+			} else {
+				_, _ = io.WriteString(config.Output, commonLogLine(config.Format, request, start, status, recorder.bytes))
+			}
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+func logAccessRecord(config AccessLogConfig, request *http.Request, bytes int64, status int, duration time.Duration, err error) {
+	ctx := request.Context()
+	routeInfo := CurrentRoute(ctx)
+
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("path", requestPath(request)),
+		slog.String("route", routeInfo.Pattern),
+		slog.String("route_name", routeInfo.Name),
+		slog.Int("status", status),
+		slog.Int64("bytes", bytes),
+		slog.Duration("duration", duration),
+		slog.String("remote_ip", ClientIP(request)),
+		slog.String("user_agent", request.UserAgent()),
+	}
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+
+	if params := matchedRouteParams(ctx); len(params) != 0 {
+		paramAttrs := make([]any, 0, len(params))
+		for paramName, paramValue := range params {
+			paramAttrs = append(paramAttrs, slog.String(paramName, paramValue))
+		}
+
+		attrs = append(attrs, slog.Group("params", paramAttrs...))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	config.Logger.LogAttrs(ctx, config.Level(status), config.Message, attrs...)
+}
+
+func commonLogLine(format AccessLogFormat, request *http.Request, start time.Time, status int, bytes int64) string {
+	user := "-"
+	if username, _, ok := request.BasicAuth(); ok && username != "" {
+		user = username
+	}
+
+	size := "-"
+	if bytes > 0 {
+		size = strconv.FormatInt(bytes, 10)
+	}
+
+	line := fmt.Sprintf(`%s - %s [%s] "%s" %d %s`,
+		ClientIP(request),
+		user,
+		start.Format(commonLogTimeFormat),
+		request.Method+" "+request.RequestURI+" "+request.Proto,
+		status,
+		size,
+	)
+
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(` "%s" "%s"`, request.Referer(), request.UserAgent())
+	}
+
+	return line + "\n"
+}
+
+func accessLogLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// requestPath returns the path the request was received with, before a mount prefix was stripped from it.
+func requestPath(request *http.Request) string {
+	if originalPath := OriginalPath(request.Context()); originalPath != "" {
+		return originalPath
+	}
+
+	return request.URL.Path
+}
@@ -0,0 +1,247 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog_Structured(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedRecord map[string]any
+	}{
+		{
+			name: "matched route",
+			path: "/users/42",
+			expectedRecord: map[string]any{
+				"level":      "INFO",
+				"msg":        "http request",
+				"method":     http.MethodGet,
+				"path":       "/users/42",
+				"route":      "/users/:id",
+				"route_name": "user",
+				"status":     float64(http.StatusCreated),
+				"bytes":      float64(2),
+				"remote_ip":  "192.0.2.1",
+				"user_agent": "test-agent",
+				"params":     map[string]any{"id": "42"},
+			},
+		},
+		{
+			name: "handler error without response",
+			path: "/fail",
+			expectedRecord: map[string]any{
+				"level":      "WARN",
+				"msg":        "http request",
+				"method":     http.MethodGet,
+				"path":       "/fail",
+				"route":      "/fail",
+				"route_name": "fail",
+				"status":     float64(http.StatusConflict),
+				"bytes":      float64(0),
+				"remote_ip":  "192.0.2.1",
+				"user_agent": "test-agent",
+				"error":      "conflict",
+			},
+		},
+		{
+			name: "not found",
+			path: "/missing",
+			expectedRecord: map[string]any{
+				"level":      "WARN",
+				"msg":        "http request",
+				"method":     http.MethodGet,
+				"path":       "/missing",
+				"route":      "",
+				"route_name": "",
+				"status":     float64(http.StatusNotFound),
+				"bytes":      float64(19),
+				"remote_ip":  "192.0.2.1",
+				"user_agent": "test-agent",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+			router.Pre(httprouter.AccessLog(httprouter.AccessLogConfig{
+				Logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+			}))
+			router.Get("/users/:id", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.WriteHeader(http.StatusCreated)
+				_, _ = responseWriter.Write([]byte("ok"))
+
+				return nil
+			}), "user")
+			router.Get("/fail", httprouter.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+				return httprouter.NewHTTPError(http.StatusConflict, errors.New("conflict"))
+			}), "fail")
+
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			request.Header.Set("User-Agent", "test-agent")
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+			assert.Contains(t, record, "time")
+			assert.Contains(t, record, "duration")
+
+			delete(record, "time")
+			delete(record, "duration")
+
+			assert.Equal(t, testCase.expectedRecord, record)
+		})
+	}
+}
+
+func TestAccessLog_SkipPathsAndSample(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	router := httprouter.New()
+	router.Pre(httprouter.AccessLog(httprouter.AccessLogConfig{
+		Logger:    slog.New(slog.NewJSONHandler(&buf, nil)),
+		SkipPaths: []string{"/health"},
+		Sample:    httprouter.SampleRatio(0),
+	}))
+	router.Get("/health", &mockHandler{}, "")
+	router.Get("/ok", &mockHandler{}, "")
+	router.Get("/fail", &mockHandler{errToReturn: errors.New("boom")}, "")
+
+	for _, path := range []string{"/health", "/ok", "/fail"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 1)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &record))
+
+	assert.Equal(t, "/fail", record["path"])
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+}
+
+func TestAccessLog_ErrorLeftToErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("user not found")
+
+	// mapper translates errors of the handlers, AccessLog must not answer the error before mapper sees it
+	mapper := func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			err := next.Handle(responseWriter, request)
+			if errors.Is(err, errNotFound) {
+				return httprouter.NewHTTPError(http.StatusNotFound, err)
+			}
+
+			return err //nolint:wrapcheck
+		})
+	}
+
+	var (
+		buf     bytes.Buffer
+		answers int
+	)
+
+	router := httprouter.New()
+	router.ErrorHandler = func(responseWriter http.ResponseWriter, request *http.Request, err error) {
+		answers++
+
+		httprouter.DefaultErrorHandler(responseWriter, request, err)
+	}
+	router.Use(mapper, httprouter.AccessLog(httprouter.AccessLogConfig{Format: httprouter.AccessLogCommon, Output: &buf}))
+	router.Get("/users", &mockHandler{errToReturn: errNotFound}, "")
+	router.Post("/users", &mockHandler{errToReturn: httprouter.NewHTTPError(http.StatusUnprocessableEntity, nil)}, "")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+
+	responseRecorder = httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Contains(t, buf.String(), `"POST /users HTTP/1.1" 422 -`)
+	assert.Equal(t, 2, answers, "every error is answered once by the router")
+}
+
+func TestAccessLog_CommonLogFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		format   httprouter.AccessLogFormat
+		expected *regexp.Regexp
+	}{
+		{
+			name:   "common",
+			format: httprouter.AccessLogCommon,
+			expected: regexp.MustCompile(
+				`^192\.0\.2\.1 - alice \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users\?page=2 HTTP/1\.1" 200 5\n$`,
+			),
+		},
+		{
+			name:   "combined",
+			format: httprouter.AccessLogCombined,
+			expected: regexp.MustCompile(
+				`^192\.0\.2\.1 - alice \[[^\]]+\] "GET /users\?page=2 HTTP/1\.1" 200 5 "http://example\.com/" "test-agent"\n$`,
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			router := httprouter.New()
+			router.Use(httprouter.AccessLog(httprouter.AccessLogConfig{
+				Format: testCase.format,
+				Output: &buf,
+			}))
+			router.Get("/users", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				_, _ = responseWriter.Write([]byte("users"))
+
+				return nil
+			}), "")
+
+			request := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+			request.SetBasicAuth("alice", "secret")
+			request.Header.Set("Referer", "http://example.com/")
+			request.Header.Set("User-Agent", "test-agent")
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			assert.Regexp(t, testCase.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// handlerError carries the error of a Handler through a net/http middleware that knows nothing about it.
+type handlerError struct {
+	err error
+}
+
+// FromHTTPHandler adapts a net/http handler to Handler, the adapted handler never returns an error.
+func FromHTTPHandler(handler http.Handler) Handler {
+	return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+		handler.ServeHTTP(responseWriter, request)
+
+		return nil
+	})
+}
+
+// ToHTTPHandler adapts a Handler to net/http handler, an error returned by the handler is answered
+// with DefaultErrorHandler.
+func ToHTTPHandler(handler Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if err := handler.Handle(responseWriter, request); err != nil {
+			DefaultErrorHandler(responseWriter, request, err)
+		}
+	})
+}
+
+// FromStdMiddleware adapts a net/http middleware to MiddlewareFunc. An error returned by the next handler
+// is passed around the net/http middleware through the request context and returned by the adapted middleware.
+func FromStdMiddleware(middleware func(http.Handler) http.Handler) MiddlewareFunc {
+	return func(next Handler) Handler {
+		stdHandler := middleware(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			err := next.Handle(responseWriter, request)
+
+			if handlerErr, ok := request.Context().Value(handlerErrorKey).(*handlerError); ok {
+				handlerErr.err = err
+			}
+		}))
+
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			handlerErr := &handlerError{}
+
+			stdHandler.ServeHTTP(responseWriter, request.WithContext(context.WithValue(request.Context(), handlerErrorKey, handlerErr)))
+
+			return handlerErr.err
+		})
+	}
+}
+
+// ToStdMiddleware adapts a MiddlewareFunc to net/http middleware, an error returned by the middleware
+// is answered with DefaultErrorHandler.
+func ToStdMiddleware(middleware MiddlewareFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return ToHTTPHandler(middleware(FromHTTPHandler(next)))
+	}
+}
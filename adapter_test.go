@@ -0,0 +1,126 @@
+package httprouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func headerStdMiddleware(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			responseWriter.Header().Add("X-Middleware", value)
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
+
+func TestFromHTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := httprouter.FromHTTPHandler(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusAccepted)
+	}))
+
+	recorder := httptest.NewRecorder()
+	err := handler.Handle(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, recorder.Code, "Status code mismatch")
+}
+
+func TestToHTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		handler        httprouter.Handler
+		expectedStatus int
+	}{
+		{"NoError", &mockHandler{}, http.StatusOK},
+		{"Error", &mockHandler{errToReturn: errors.New("handler error")}, http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			httprouter.ToHTTPHandler(testCase.handler).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, testCase.expectedStatus, recorder.Code, "Status code mismatch")
+		})
+	}
+}
+
+func TestFromStdMiddleware_PropagatesError(t *testing.T) {
+	t.Parallel()
+
+	errHandler := errors.New("handler error")
+
+	middleware := httprouter.FromStdMiddleware(headerStdMiddleware("outer"))
+	innerMiddleware := httprouter.FromStdMiddleware(headerStdMiddleware("inner"))
+
+	handler := middleware(innerMiddleware(&mockHandler{errToReturn: errHandler}))
+
+	recorder := httptest.NewRecorder()
+	err := handler.Handle(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.ErrorIs(t, err, errHandler)
+	assert.Equal(t, []string{"outer", "inner"}, recorder.Header().Values("X-Middleware"))
+}
+
+func TestToStdMiddleware(t *testing.T) {
+	t.Parallel()
+
+	errUnauthorized := errors.New("unauthorized")
+
+	middleware := httprouter.ToStdMiddleware(func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if request.Header.Get("Authorization") == "" {
+				return errUnauthorized
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	})
+
+	handler := middleware(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusNoContent)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code, "Status code mismatch")
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "token")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code, "Status code mismatch")
+}
+
+func TestRouter_HandleHTTP(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Use(httprouter.FromStdMiddleware(headerStdMiddleware("std")))
+	router.HandleHTTP("/test", []string{http.MethodGet}, http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusAccepted)
+	}), "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "std", recorder.Header().Get("X-Middleware"))
+}
@@ -0,0 +1,81 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+const DefaultAPIKeyHeader = "X-API-Key"
+
+type APIKeyAuthConfig struct {
+	// Header, Query and Cookie name where the key is looked for, in this order.
+	// DefaultAPIKeyHeader is used when all of them are empty.
+	Header string
+	Query  string
+	Cookie string
+	// Realm is sent in the WWW-Authenticate challenge, "Restricted" is used when it is empty.
+	Realm string
+	// Lookup returns the identity of the key, ok is false for unknown keys. It should not leak through timing
+	// how much of a key matched, e.g. by looking up a hash of the key. It is required.
+	Lookup func(ctx context.Context, key string) (identity Identity, ok bool)
+}
+
+// APIKeyAuth authenticates requests with an API key from a header, a query parameter or a cookie.
+// Requests without a valid key are answered with 401 Unauthorized and an APIKey challenge by the router
+// error handler. The identity of the key is available with IdentityFromContext.
+func APIKeyAuth(config APIKeyAuthConfig) MiddlewareFunc {
+	if config.Lookup == nil {
+		panic("httprouter: API key lookup is nil")
+	}
+
+	if config.Header == "" && config.Query == "" && config.Cookie == "" {
+		config.Header = DefaultAPIKeyHeader
+	}
+
+	if config.Realm == "" {
+		config.Realm = defaultRealm
+	}
+
+	challenge := "APIKey realm=" + strconv.Quote(config.Realm)
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			key := apiKey(config, request)
+			if key == "" {
+				return unauthorized(responseWriter, challenge, ErrMissingCredentials)
+			}
+
+			identity, ok := config.Lookup(request.Context(), key)
+			if !ok {
+				return unauthorized(responseWriter, challenge, ErrInvalidCredentials)
+			}
+
+			identity.Scheme = "APIKey"
+
+			return next.Handle(responseWriter, withIdentity(request, identity)) //nolint:wrapcheck
+		})
+	}
+}
+
+func apiKey(config APIKeyAuthConfig, request *http.Request) string {
+	if config.Header != "" {
+		if key := request.Header.Get(config.Header); key != "" {
+			return key
+		}
+	}
+
+	if config.Query != "" {
+		if key := request.URL.Query().Get(config.Query); key != "" {
+			return key
+		}
+	}
+
+	if config.Cookie != "" {
+		if cookie, err := request.Cookie(config.Cookie); err == nil {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
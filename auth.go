@@ -0,0 +1,40 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+const defaultRealm = "Restricted"
+
+// Identity is the client authenticated by BasicAuth, APIKeyAuth or JWTAuth.
+type Identity struct {
+	Subject string
+	// Scheme is "Basic", "APIKey" or "Bearer".
+	Scheme string
+	Scopes []string
+	// Claims are the claims of a JWT, they are nil for other schemes.
+	Claims map[string]any
+}
+
+// IdentityFromContext returns the authenticated client, ok is false if the request was not authenticated.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey).(Identity)
+
+	return identity, ok
+}
+
+func withIdentity(request *http.Request, identity Identity) *http.Request {
+	if state, ok := request.Context().Value(routeStateKey).(*routeState); ok {
+		state.authenticated = true
+	}
+
+	return request.WithContext(context.WithValue(request.Context(), identityKey, identity))
+}
+
+// unauthorized sets the challenge and returns the 401 error the router error handler answers with.
+func unauthorized(responseWriter http.ResponseWriter, challenge string, err error) error {
+	responseWriter.Header().Add("WWW-Authenticate", challenge)
+
+	return NewHTTPError(http.StatusUnauthorized, err)
+}
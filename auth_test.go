@@ -0,0 +1,189 @@
+package httprouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func identityHandler(responseWriter http.ResponseWriter, request *http.Request) error {
+	identity, ok := httprouter.IdentityFromContext(request.Context())
+	if !ok {
+		return httprouter.NewHTTPError(http.StatusInternalServerError, nil)
+	}
+
+	_, _ = responseWriter.Write([]byte(identity.Scheme + " " + identity.Subject))
+
+	return nil
+}
+
+func TestBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	users := map[string]string{"alice": "secret"}
+
+	testCases := []struct {
+		name              string
+		username          string
+		password          string
+		noCredentials     bool
+		expectedStatus    int
+		expectedBody      string
+		expectedChallenge string
+	}{
+		{
+			name:           "valid credentials",
+			username:       "alice",
+			password:       "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Basic alice",
+		},
+		{
+			name:              "wrong password",
+			username:          "alice",
+			password:          "secre",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Basic realm="admin", charset="UTF-8"`,
+		},
+		{
+			name:              "unknown user with the empty password",
+			username:          "bob",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Basic realm="admin", charset="UTF-8"`,
+		},
+		{
+			name:              "missing credentials",
+			noCredentials:     true,
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Basic realm="admin", charset="UTF-8"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Use(httprouter.BasicAuth(httprouter.BasicAuthConfig{
+				Realm: "admin",
+				Lookup: func(_ context.Context, username string) (string, httprouter.Identity, bool) {
+					password, ok := users[username]
+
+					return password, httprouter.Identity{}, ok
+				},
+			}))
+			router.Get("/admin", httprouter.HandlerFunc(identityHandler), "")
+
+			request := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if !testCase.noCredentials {
+				request.SetBasicAuth(testCase.username, testCase.password)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+			assert.Equal(t, testCase.expectedChallenge, responseRecorder.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		config         httprouter.APIKeyAuthConfig
+		prepare        func(request *http.Request)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "key in the default header",
+			prepare: func(request *http.Request) {
+				request.Header.Set("X-API-Key", "key-1")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "APIKey service-1",
+		},
+		{
+			name:   "key in the query",
+			config: httprouter.APIKeyAuthConfig{Header: "X-Key", Query: "api_key"},
+			prepare: func(request *http.Request) {
+				request.URL.RawQuery = "api_key=key-1"
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "APIKey service-1",
+		},
+		{
+			name:   "key in a cookie",
+			config: httprouter.APIKeyAuthConfig{Cookie: "api_key"},
+			prepare: func(request *http.Request) {
+				request.AddCookie(&http.Cookie{Name: "api_key", Value: "key-1"})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "APIKey service-1",
+		},
+		{
+			name:   "query is not used when not configured",
+			config: httprouter.APIKeyAuthConfig{Header: "X-Key"},
+			prepare: func(request *http.Request) {
+				request.URL.RawQuery = "api_key=key-1"
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Unauthorized\n",
+		},
+		{
+			name: "unknown key",
+			prepare: func(request *http.Request) {
+				request.Header.Set("X-API-Key", "key-2")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Unauthorized\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := testCase.config
+			config.Lookup = func(_ context.Context, key string) (httprouter.Identity, bool) {
+				if key != "key-1" {
+					return httprouter.Identity{}, false
+				}
+
+				return httprouter.Identity{Subject: "service-1"}, true
+			}
+
+			router := httprouter.New()
+			router.Use(httprouter.APIKeyAuth(config))
+			router.Get("/reports", httprouter.HandlerFunc(identityHandler), "")
+
+			request := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			testCase.prepare(request)
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+
+			if testCase.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, `APIKey realm="Restricted"`, responseRecorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
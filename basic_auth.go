@@ -0,0 +1,59 @@
+package httprouter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+)
+
+type BasicAuthConfig struct {
+	// Realm is sent in the WWW-Authenticate challenge, "Restricted" is used when it is empty.
+	Realm string
+	// Lookup returns the password and the identity of the user, ok is false for unknown users.
+	// The subject of the identity defaults to the username. It is required.
+	Lookup func(ctx context.Context, username string) (password string, identity Identity, ok bool)
+}
+
+// BasicAuth authenticates requests with HTTP Basic credentials, the password is compared in constant time.
+// Requests without valid credentials are answered with 401 Unauthorized and a Basic challenge by the router
+// error handler. The identity of the user is available with IdentityFromContext.
+func BasicAuth(config BasicAuthConfig) MiddlewareFunc {
+	if config.Lookup == nil {
+		panic("httprouter: basic auth lookup is nil")
+	}
+
+	if config.Realm == "" {
+		config.Realm = defaultRealm
+	}
+
+	challenge := "Basic realm=" + strconv.Quote(config.Realm) + `, charset="UTF-8"`
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			username, password, ok := request.BasicAuth()
+			if !ok {
+				return unauthorized(responseWriter, challenge, ErrMissingCredentials)
+			}
+
+			expectedPassword, identity, found := config.Lookup(request.Context(), username)
+
+			// hashing makes the comparison take the same time whatever the length of the passwords
+			passwordHash := sha256.Sum256([]byte(password))
+			expectedHash := sha256.Sum256([]byte(expectedPassword))
+
+			if subtle.ConstantTimeCompare(passwordHash[:], expectedHash[:]) != 1 || !found {
+				return unauthorized(responseWriter, challenge, ErrInvalidCredentials)
+			}
+
+			if identity.Subject == "" {
+				identity.Subject = username
+			}
+
+			identity.Scheme = "Basic"
+
+			return next.Handle(responseWriter, withIdentity(request, identity)) //nolint:wrapcheck
+		})
+	}
+}
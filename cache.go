@@ -0,0 +1,274 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CacheConfig struct {
+	// Cache keeps the responses, share it between routes to invalidate them from handlers. It is required.
+	Cache *ResponseCache
+	// TTL is how long a response is fresh when it has no max-age or s-maxage Cache-Control directive, it is required.
+	TTL time.Duration
+	// StaleWhileRevalidate is how long a response is served stale while it is refreshed in the background,
+	// when it has no stale-while-revalidate Cache-Control directive.
+	StaleWhileRevalidate time.Duration
+	// Query and Headers are the query parameters and request headers the responses differ by,
+	// in addition to the route and its params and the headers listed by the Vary header of the response.
+	Query   []string
+	Headers []string
+}
+
+// Cache serves GET requests from the responses cached for their route, params and the selected query parameters
+// and headers. Only 200 OK responses without Set-Cookie are cached, unless their Cache-Control header has
+// the no-store, no-cache or private directive. A request whose Cache-Control header has the no-store directive
+// bypasses the cache, no-cache or max-age=0 refreshes the cached response and max-age limits the age
+// of the response served. Served responses have an Age header. Cache reads the matched route, so it must be
+// added with Use or With rather than Pre.
+//
+// As required by RFC 9111, section 3.5, responses to requests with an Authorization header or authenticated
+// by BasicAuth, APIKeyAuth or JWTAuth, before or after Cache, are only stored when their Cache-Control header
+// has the public, must-revalidate or s-maxage directive, and only such responses are served to those requests.
+func Cache(config CacheConfig) MiddlewareFunc {
+	if config.Cache == nil {
+		panic("httprouter: response cache is nil")
+	}
+
+	if config.TTL <= 0 {
+		panic("httprouter: cache TTL must be positive")
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if request.Method != http.MethodGet {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			directives := parseCacheControl(request.Header.Get("Cache-Control"))
+			if _, ok := directives["no-store"]; ok {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			route := cacheRoute(request.Context())
+			primaryKey := cacheKey(config, route, request)
+
+			if _, ok := directives["no-cache"]; !ok {
+				now := config.Cache.now()
+
+				entry, ok := config.Cache.get(primaryKey, request, now)
+				if ok && (entry.shared || !authorizedRequest(request)) && acceptableAge(directives, now.Sub(entry.storedAt)) {
+					if now.Sub(entry.storedAt) > entry.ttl && config.Cache.startRevalidation(entry) {
+						// the request is detached before the goroutine starts, middlewares of the request
+						// keep using the route state the revalidation must not share
+						ctx, _ := isolateRouteState(context.WithoutCancel(request.Context()))
+
+						go revalidate(config, next, request.Clone(ctx), entry)
+					}
+
+					serveCached(responseWriter, entry, now)
+
+					return nil
+				}
+			}
+
+			writer := newBufferedWriter()
+
+			err := next.Handle(writer, request)
+			if err == nil {
+				storeResponse(config, route, primaryKey, writer, request)
+			}
+
+			writer.writeTo(responseWriter)
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+// revalidate refreshes the stale response in the background with a request detached from the request
+// which was already answered with the stale response.
+func revalidate(config CacheConfig, next Handler, request *http.Request, entry *cachedResponse) {
+	defer config.Cache.finishRevalidation(entry)
+
+	// a panic of the handler must not crash the server, the stale response keeps being served until it expires
+	defer func() {
+		_ = recover()
+	}()
+
+	writer := newBufferedWriter()
+
+	if err := next.Handle(writer, request); err == nil {
+		storeResponse(config, entry.route, entry.primaryKey, writer, request)
+	}
+}
+
+func serveCached(responseWriter http.ResponseWriter, entry *cachedResponse, now time.Time) {
+	header := responseWriter.Header()
+	for name, values := range entry.header {
+		header[name] = slices.Clone(values)
+	}
+
+	header.Set("Age", strconv.Itoa(int(now.Sub(entry.storedAt).Seconds())))
+
+	responseWriter.WriteHeader(entry.status)
+	_, _ = responseWriter.Write(entry.body)
+}
+
+func storeResponse(config CacheConfig, route, primaryKey string, writer *bufferedWriter, request *http.Request) {
+	if writer.status != http.StatusOK || writer.header.Get("Set-Cookie") != "" {
+		return
+	}
+
+	directives := parseCacheControl(writer.header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return
+		}
+	}
+
+	shared := false
+
+	for _, directive := range []string{"public", "must-revalidate", "s-maxage"} {
+		if _, ok := directives[directive]; ok {
+			shared = true
+		}
+	}
+
+	if !shared && authorizedRequest(request) {
+		return
+	}
+
+	var vary []string
+
+	for _, value := range writer.header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name == "*" {
+				return
+			} else if name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	ttl := config.TTL
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok {
+		ttl = seconds
+	} else if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+		ttl = seconds
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	staleTTL := config.StaleWhileRevalidate
+	if seconds, ok := directiveSeconds(directives, "stale-while-revalidate"); ok {
+		staleTTL = seconds
+	}
+
+	config.Cache.set(&cachedResponse{
+		primaryKey: primaryKey,
+		route:      route,
+		status:     writer.status,
+		header:     writer.header.Clone(),
+		body:       slices.Clone(writer.body.Bytes()),
+		storedAt:   config.Cache.now(),
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		shared:     shared,
+	}, vary, request)
+}
+
+// authorizedRequest reports whether the request has credentials in the Authorization header or was authenticated,
+// the route state tells about the authentication by a middleware running after Cache.
+func authorizedRequest(request *http.Request) bool {
+	if request.Header.Get("Authorization") != "" {
+		return true
+	}
+
+	if _, ok := IdentityFromContext(request.Context()); ok {
+		return true
+	}
+
+	state, ok := request.Context().Value(routeStateKey).(*routeState)
+
+	return ok && state.authenticated
+}
+
+// cacheRoute returns the name of the matched route, or its pattern for unnamed routes.
+func cacheRoute(ctx context.Context) string {
+	routeInfo := CurrentRoute(ctx)
+	if routeInfo.Name != "" {
+		return routeInfo.Name
+	}
+
+	return routeInfo.Pattern
+}
+
+func cacheKey(config CacheConfig, route string, request *http.Request) string {
+	var key strings.Builder
+
+	key.WriteString(route)
+
+	params := routeParamsFrom(request.Context())
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		key.WriteString("\x00" + name + "=" + params[name])
+	}
+
+	query := request.URL.Query()
+	for _, name := range config.Query {
+		key.WriteString("\x00" + strings.Join(query[name], ","))
+	}
+
+	for _, name := range config.Headers {
+		key.WriteString("\x00" + strings.Join(request.Header.Values(name), ","))
+	}
+
+	return key.String()
+}
+
+func acceptableAge(directives map[string]string, age time.Duration) bool {
+	maxAge, ok := directiveSeconds(directives, "max-age")
+
+	return !ok || age <= maxAge
+}
+
+// parseCacheControl returns the directives of a Cache-Control header by lower-cased name.
+func parseCacheControl(header string) map[string]string {
+	directives := make(map[string]string)
+
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name != "" {
+			directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return directives
+}
+
+func directiveSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,340 @@
+package httprouter_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheStep struct {
+	advance      time.Duration
+	path         string
+	headers      map[string]string
+	invalidate   string
+	expectedBody string
+	expectedAge  string
+}
+
+func newCacheRouter(cache *httprouter.ResponseCache) httprouter.Router {
+	var calls atomic.Int64
+
+	respond := func(header http.Header) httprouter.HandlerFunc {
+		return func(responseWriter http.ResponseWriter, request *http.Request) error {
+			for name, values := range header {
+				responseWriter.Header()[name] = values
+			}
+
+			_, _ = fmt.Fprintf(responseWriter, "%s%s %s #%d", request.URL.Path, request.Header.Get("Accept-Language"),
+				request.URL.Query().Get("fields"), calls.Add(1))
+
+			return nil
+		}
+	}
+
+	router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	router.Use(httprouter.Cache(httprouter.CacheConfig{
+		Cache:                cache,
+		TTL:                  10 * time.Second,
+		StaleWhileRevalidate: 5 * time.Second,
+		Query:                []string{"fields"},
+	}))
+
+	router.Get("/users/:id", respond(nil), "user")
+	router.Get("/greeting", respond(http.Header{"Vary": {"Accept-Language"}}), "greeting")
+	router.Get("/private", respond(http.Header{"Cache-Control": {"private"}}), "private")
+	router.Get("/short", respond(http.Header{"Cache-Control": {"max-age=1, stale-while-revalidate=0"}}), "short")
+	router.Get("/cookie", respond(http.Header{"Set-Cookie": {"session=1"}}), "cookie")
+
+	return router
+}
+
+//nolint:funlen
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		steps []cacheStep
+	}{
+		{
+			name: "cached by route, params and query",
+			steps: []cacheStep{
+				{path: "/users/1", expectedBody: "/users/1  #1"},
+				{advance: 3 * time.Second, path: "/users/1", expectedBody: "/users/1  #1", expectedAge: "3"},
+				{path: "/users/2", expectedBody: "/users/2  #2"},
+				{path: "/users/1?fields=name", expectedBody: "/users/1 name #3"},
+				{path: "/users/1?fields=name&page=2", expectedBody: "/users/1 name #3", expectedAge: "0"},
+			},
+		},
+		{
+			name: "expired response",
+			steps: []cacheStep{
+				{path: "/users/1", expectedBody: "/users/1  #1"},
+				{advance: 16 * time.Second, path: "/users/1", expectedBody: "/users/1  #2"},
+			},
+		},
+		{
+			name: "variants of the Vary header",
+			steps: []cacheStep{
+				{path: "/greeting", headers: map[string]string{"Accept-Language": "en"}, expectedBody: "/greetingen  #1"},
+				{path: "/greeting", headers: map[string]string{"Accept-Language": "fr"}, expectedBody: "/greetingfr  #2"},
+				{path: "/greeting", headers: map[string]string{"Accept-Language": "en"}, expectedBody: "/greetingen  #1", expectedAge: "0"},
+			},
+		},
+		{
+			name: "request Cache-Control",
+			steps: []cacheStep{
+				{path: "/users/1", expectedBody: "/users/1  #1"},
+				{path: "/users/1", headers: map[string]string{"Cache-Control": "no-store"}, expectedBody: "/users/1  #2"},
+				{advance: 2 * time.Second, path: "/users/1", headers: map[string]string{"Cache-Control": "max-age=1"}, expectedBody: "/users/1  #3"},
+				{path: "/users/1", expectedBody: "/users/1  #3", expectedAge: "0"},
+				{path: "/users/1", headers: map[string]string{"Cache-Control": "no-cache"}, expectedBody: "/users/1  #4"},
+				{path: "/users/1", expectedBody: "/users/1  #4", expectedAge: "0"},
+			},
+		},
+		{
+			name: "response Cache-Control",
+			steps: []cacheStep{
+				{path: "/private", expectedBody: "/private  #1"},
+				{path: "/private", expectedBody: "/private  #2"},
+				{path: "/cookie", expectedBody: "/cookie  #3"},
+				{path: "/cookie", expectedBody: "/cookie  #4"},
+				{path: "/short", expectedBody: "/short  #5"},
+				{advance: time.Second, path: "/short", expectedBody: "/short  #5", expectedAge: "1"},
+				{advance: 2 * time.Second, path: "/short", expectedBody: "/short  #6"},
+			},
+		},
+		{
+			name: "invalidation by route name",
+			steps: []cacheStep{
+				{path: "/users/1", expectedBody: "/users/1  #1"},
+				{path: "/greeting", expectedBody: "/greeting  #2"},
+				{invalidate: "user", path: "/users/1", expectedBody: "/users/1  #3"},
+				{path: "/greeting", expectedBody: "/greeting  #2", expectedAge: "0"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock()
+			cache := httprouter.NewResponseCache(httprouter.ResponseCacheConfig{Now: clock.Now})
+			router := newCacheRouter(cache)
+
+			for idx, step := range testCase.steps {
+				clock.Advance(step.advance)
+
+				if step.invalidate != "" {
+					cache.InvalidateRoute(step.invalidate)
+				}
+
+				request := httptest.NewRequest(http.MethodGet, step.path, nil)
+				for name, value := range step.headers {
+					request.Header.Set(name, value)
+				}
+
+				responseRecorder := httptest.NewRecorder()
+				router.ServeHTTP(responseRecorder, request)
+
+				assert.Equal(t, http.StatusOK, responseRecorder.Code, "step %d", idx)
+				assert.Equal(t, step.expectedBody, responseRecorder.Body.String(), "step %d", idx)
+				assert.Equal(t, step.expectedAge, responseRecorder.Header().Get("Age"), "step %d", idx)
+			}
+		})
+	}
+}
+
+func TestCache_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	cache := httprouter.NewResponseCache(httprouter.ResponseCacheConfig{Now: clock.Now})
+	router := newCacheRouter(cache)
+
+	get := func() string {
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		return responseRecorder.Body.String()
+	}
+
+	require.Equal(t, "/users/1  #1", get())
+
+	clock.Advance(12 * time.Second)
+	assert.Equal(t, "/users/1  #1", get(), "the stale response is served while it is refreshed")
+
+	assert.Eventually(t, func() bool {
+		return get() == "/users/1  #2"
+	}, time.Second, time.Millisecond)
+}
+
+func TestCache_StaleWhileRevalidate_RouteState(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	cache := httprouter.NewResponseCache(httprouter.ResponseCacheConfig{Now: clock.Now})
+
+	var calls atomic.Int64
+
+	router := httprouter.New()
+	// AccessLog reads the request ID from the route state after the stale response is served,
+	// while RequestID of the background revalidation sets it
+	router.Pre(httprouter.AccessLog(httprouter.AccessLogConfig{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}))
+	router.Use(
+		httprouter.Cache(httprouter.CacheConfig{Cache: cache, TTL: time.Second, StaleWhileRevalidate: time.Minute}),
+		httprouter.RequestID(httprouter.RequestIDConfig{}),
+	)
+	router.Get("/report", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		_, _ = fmt.Fprintf(responseWriter, "report #%d", calls.Add(1))
+
+		return nil
+	}), "report")
+
+	get := func() string {
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+		return responseRecorder.Body.String()
+	}
+
+	require.Equal(t, "report #1", get())
+
+	clock.Advance(2 * time.Second)
+	assert.Equal(t, "report #1", get())
+
+	assert.Eventually(t, func() bool {
+		return get() == "report #2"
+	}, time.Second, time.Millisecond)
+}
+
+func TestResponseCache_MaxEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := httprouter.NewResponseCache(httprouter.ResponseCacheConfig{MaxEntries: 2})
+	router := newCacheRouter(cache)
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/1", "/users/3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 2, cache.Len())
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.Equal(t, "/users/1  #1", responseRecorder.Body.String(), "the recently used response is kept")
+
+	responseRecorder = httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+	assert.Equal(t, "/users/2  #4", responseRecorder.Body.String(), "the least recently used response is evicted")
+}
+
+//nolint:funlen
+func TestCache_Authorization(t *testing.T) {
+	t.Parallel()
+
+	basicAuth := httprouter.BasicAuth(httprouter.BasicAuthConfig{
+		Lookup: func(_ context.Context, username string) (string, httprouter.Identity, bool) {
+			return "secret", httprouter.Identity{}, username == "alice" || username == "bob"
+		},
+	})
+	apiKeyAuth := httprouter.APIKeyAuth(httprouter.APIKeyAuthConfig{
+		Lookup: func(_ context.Context, key string) (httprouter.Identity, bool) {
+			return httprouter.Identity{Subject: key}, key == "alice" || key == "bob"
+		},
+	})
+
+	testCases := []struct {
+		name           string
+		auth           httprouter.MiddlewareFunc
+		authFirst      bool
+		apiKey         bool
+		cacheControl   string
+		expectedBodies []string
+	}{
+		{
+			name:           "basic auth before cache",
+			auth:           basicAuth,
+			authFirst:      true,
+			expectedBodies: []string{"Basic alice #1", "Basic bob #2", "Basic alice #3"},
+		},
+		{
+			name:           "cache before basic auth",
+			auth:           basicAuth,
+			expectedBodies: []string{"Basic alice #1", "Basic bob #2", "Basic alice #3"},
+		},
+		{
+			name:           "cache before api key auth without the Authorization header",
+			auth:           apiKeyAuth,
+			apiKey:         true,
+			expectedBodies: []string{"APIKey alice #1", "APIKey bob #2", "APIKey alice #3"},
+		},
+		{
+			name:           "public response",
+			auth:           basicAuth,
+			authFirst:      true,
+			cacheControl:   "public",
+			expectedBodies: []string{"Basic alice #1", "Basic alice #1", "Basic alice #1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := httprouter.NewResponseCache(httprouter.ResponseCacheConfig{})
+			cacheMiddleware := httprouter.Cache(httprouter.CacheConfig{Cache: cache, TTL: time.Minute})
+
+			router := httprouter.New()
+			if testCase.authFirst {
+				router.Use(testCase.auth, cacheMiddleware)
+			} else {
+				router.Use(cacheMiddleware, testCase.auth)
+			}
+
+			var calls atomic.Int64
+
+			router.Get("/me", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+				identity, _ := httprouter.IdentityFromContext(request.Context())
+
+				if testCase.cacheControl != "" {
+					responseWriter.Header().Set("Cache-Control", testCase.cacheControl)
+				}
+
+				_, _ = fmt.Fprintf(responseWriter, "%s %s #%d", identity.Scheme, identity.Subject, calls.Add(1))
+
+				return nil
+			}), "me")
+
+			for idx, username := range []string{"alice", "bob", "alice"} {
+				request := httptest.NewRequest(http.MethodGet, "/me", nil)
+				if testCase.apiKey {
+					request.Header.Set(httprouter.DefaultAPIKeyHeader, username)
+				} else {
+					request.SetBasicAuth(username, "secret")
+				}
+
+				responseRecorder := httptest.NewRecorder()
+				router.ServeHTTP(responseRecorder, request)
+
+				assert.Equal(t, http.StatusOK, responseRecorder.Code, "request %d", idx)
+				assert.Equal(t, testCase.expectedBodies[idx], responseRecorder.Body.String(), "request %d", idx)
+			}
+		})
+	}
+}
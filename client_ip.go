@@ -0,0 +1,296 @@
+package httprouter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Forwarding headers ProxyHeaders resolves the client from.
+const (
+	HeaderForwarded       = "Forwarded"
+	HeaderXForwardedFor   = "X-Forwarded-For"
+	HeaderXRealIP         = "X-Real-IP"
+	headerXForwardedHost  = "X-Forwarded-Host"
+	headerXForwardedProto = "X-Forwarded-Proto"
+)
+
+type ProxyHeadersConfig struct {
+	// TrustedProxies are the addresses of the proxies allowed to set forwarding headers, as CIDRs or single IPs,
+	// e.g. "10.0.0.0/8". Forwarding headers of requests from other addresses are ignored.
+	TrustedProxies []string
+	// Headers are the forwarding headers read, the first one present is used. Only HeaderXForwardedFor is read
+	// when it is empty. List only the headers the proxies set, a client can send any other header
+	// and the proxies pass it through.
+	Headers []string
+}
+
+// hop is a proxy or the client in a chain of forwarding headers, with the scheme and host the next proxy received.
+type hop struct {
+	addr  netip.Addr
+	proto string
+	host  string
+}
+
+// ProxyHeaders resolves the client IP of requests received from trusted proxies. It walks the chain of addresses
+// in the forwarding header from the right and stops at the first one that is not a trusted proxy. The request
+// Host and URL scheme and host are rewritten from the proto and host of the same hop in the Forwarded header,
+// or from the X-Forwarded-Proto and X-Forwarded-Host headers. The client IP is available with ClientIPFromContext
+// and is used by AccessLog and RateLimitByIP, add ProxyHeaders with Pre before them.
+func ProxyHeaders(config ProxyHeadersConfig) MiddlewareFunc {
+	trustedProxies := make([]netip.Prefix, 0, len(config.TrustedProxies))
+
+	for _, trustedProxy := range config.TrustedProxies {
+		prefix, err := parsePrefix(trustedProxy)
+		if err != nil {
+			panic(fmt.Sprintf("httprouter: invalid trusted proxy %q", trustedProxy))
+		}
+
+		trustedProxies = append(trustedProxies, prefix)
+	}
+
+	if len(config.Headers) == 0 {
+		config.Headers = []string{HeaderXForwardedFor}
+	}
+
+	trusted := func(addr netip.Addr) bool {
+		return slices.ContainsFunc(trustedProxies, func(prefix netip.Prefix) bool {
+			return prefix.Contains(addr.Unmap())
+		})
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			client := hop{addr: remoteAddr(request)}
+
+			if client.addr.IsValid() && trusted(client.addr) {
+				client = resolveClient(request, config.Headers, client, trusted)
+
+				if client.proto != "" {
+					request.URL.Scheme = client.proto
+				}
+
+				if client.host != "" {
+					request.Host = client.host
+					request.URL.Host = client.host
+				}
+			}
+
+			if !client.addr.IsValid() {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			clientIP := client.addr.Unmap().String()
+			ctx := context.WithValue(request.Context(), clientIPKey, clientIP)
+
+			// Pre middlewares added before ProxyHeaders find the client IP in the state
+			if state, ok := ctx.Value(routeStateKey).(*routeState); ok {
+				state.clientIP = clientIP
+			}
+
+			return next.Handle(responseWriter, request.WithContext(ctx)) //nolint:wrapcheck
+		})
+	}
+}
+
+// ClientIPFromContext returns the client IP resolved by the ProxyHeaders middleware, or an empty string if there is none.
+func ClientIPFromContext(ctx context.Context) string {
+	if clientIP, ok := ctx.Value(clientIPKey).(string); ok {
+		return clientIP
+	}
+
+	if state, ok := ctx.Value(routeStateKey).(*routeState); ok {
+		return state.clientIP
+	}
+
+	return ""
+}
+
+// ClientIP returns the client IP resolved by the ProxyHeaders middleware, or the IP of the request remote address.
+func ClientIP(request *http.Request) string {
+	if clientIP := ClientIPFromContext(request.Context()); clientIP != "" {
+		return clientIP
+	}
+
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
+
+// resolveClient returns the rightmost hop of the first forwarding header present that is not a trusted proxy.
+// An invalid address ends the chain, the hop after it is the client then.
+func resolveClient(request *http.Request, headers []string, remote hop, trusted func(netip.Addr) bool) hop {
+	for _, header := range headers {
+		values := request.Header.Values(header)
+		if len(values) == 0 {
+			continue
+		}
+
+		var hops []hop
+
+		switch http.CanonicalHeaderKey(header) {
+		case HeaderForwarded:
+			hops = forwardedHops(values)
+		case http.CanonicalHeaderKey(HeaderXRealIP):
+			hops = []hop{{addr: parseHopAddr(values[0])}}
+		default:
+			hops = forwardedForHops(request, values)
+		}
+
+		client := remote
+
+		for idx := len(hops) - 1; idx >= 0; idx-- {
+			if !hops[idx].addr.IsValid() {
+				break
+			}
+
+			client = hops[idx]
+
+			if !trusted(client.addr) {
+				break
+			}
+		}
+
+		return client
+	}
+
+	return remote
+}
+
+// forwardedHops parses the elements of RFC 7239 Forwarded headers.
+func forwardedHops(values []string) []hop {
+	var hops []hop
+
+	for _, element := range splitQuoted(strings.Join(values, ","), ',') {
+		var forwarded hop
+
+		for _, pair := range splitQuoted(element, ';') {
+			name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			value = strings.Trim(value, `"`)
+
+			switch strings.ToLower(name) {
+			case "for":
+				forwarded.addr = parseHopAddr(value)
+			case "proto":
+				forwarded.proto = strings.ToLower(value)
+			case "host":
+				forwarded.host = value
+			}
+		}
+
+		hops = append(hops, forwarded)
+	}
+
+	return hops
+}
+
+// forwardedForHops parses X-Forwarded-For headers, the X-Forwarded-Proto and X-Forwarded-Host values are
+// matched to the hops when there is one per hop, otherwise the last value applies to the client.
+func forwardedForHops(request *http.Request, values []string) []hop {
+	addrs := splitList(values)
+	protos := splitList(request.Header.Values(headerXForwardedProto))
+	hosts := splitList(request.Header.Values(headerXForwardedHost))
+
+	hops := make([]hop, len(addrs))
+
+	for idx, addr := range addrs {
+		hops[idx].addr = parseHopAddr(addr)
+		hops[idx].proto = strings.ToLower(listValue(protos, idx, len(addrs)))
+		hops[idx].host = listValue(hosts, idx, len(addrs))
+	}
+
+	return hops
+}
+
+func listValue(values []string, idx, hops int) string {
+	switch {
+	case len(values) == hops:
+		return values[idx]
+	case len(values) > 0:
+		return values[len(values)-1]
+	default:
+		return ""
+	}
+}
+
+func splitList(values []string) []string {
+	var list []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// splitQuoted splits the value on the separator outside of quoted strings.
+func splitQuoted(value string, separator byte) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for idx := 0; idx < len(value); idx++ {
+		switch value[idx] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			idx++
+		case separator:
+			if !quoted {
+				parts = append(parts, value[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+// parseHopAddr parses an IP address with an optional port, e.g. "192.0.2.60", "192.0.2.60:4711",
+// "2001:db8::17" or "[2001:db8::17]:4711". It returns the zero Addr for obfuscated identifiers like "unknown".
+func parseHopAddr(value string) netip.Addr {
+	value = strings.TrimSpace(value)
+
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr()
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return addr
+}
+
+func remoteAddr(request *http.Request) netip.Addr {
+	return parseHopAddr(request.RemoteAddr)
+}
+
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+
+		return prefix.Masked(), err //nolint:wrapcheck
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err //nolint:wrapcheck
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
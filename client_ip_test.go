@@ -0,0 +1,188 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:funlen
+func TestProxyHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		config           httprouter.ProxyHeadersConfig
+		remoteAddr       string
+		headers          map[string][]string
+		expectedClientIP string
+		expectedScheme   string
+		expectedHost     string
+	}{
+		{
+			name:             "untrusted remote address",
+			remoteAddr:       "203.0.113.9:1234",
+			headers:          map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expectedClientIP: "203.0.113.9",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "X-Forwarded-For through two trusted proxies",
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"X-Forwarded-For": {"198.51.100.1, 10.0.0.1"}},
+			expectedClientIP: "198.51.100.1",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "spoofed X-Forwarded-For entry left of the client",
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"X-Forwarded-For": {"1.1.1.1", "198.51.100.1, 10.0.0.1"}},
+			expectedClientIP: "198.51.100.1",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "all hops trusted",
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.1"}},
+			expectedClientIP: "10.0.0.3",
+			expectedHost:     "example.com",
+		},
+		{
+			name:       "X-Forwarded-Proto and X-Forwarded-Host",
+			remoteAddr: "10.0.0.2:1234",
+			headers: map[string][]string{
+				"X-Forwarded-For":   {"198.51.100.1"},
+				"X-Forwarded-Proto": {"https"},
+				"X-Forwarded-Host":  {"api.example.com"},
+			},
+			expectedClientIP: "198.51.100.1",
+			expectedScheme:   "https",
+			expectedHost:     "api.example.com",
+		},
+		{
+			name: "Forwarded takes precedence",
+			config: httprouter.ProxyHeadersConfig{
+				Headers: []string{httprouter.HeaderForwarded, httprouter.HeaderXForwardedFor},
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: map[string][]string{
+				"Forwarded":       {`for="[2001:db8:cafe::17]:4711";proto=https;host=shop.example.com, for=10.0.0.1;proto=http`},
+				"X-Forwarded-For": {"198.51.100.1"},
+			},
+			expectedClientIP: "2001:db8:cafe::17",
+			expectedScheme:   "https",
+			expectedHost:     "shop.example.com",
+		},
+		{
+			name:             "obfuscated Forwarded identifier",
+			config:           httprouter.ProxyHeadersConfig{Headers: []string{httprouter.HeaderForwarded}},
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"Forwarded": {"for=_hidden, for=10.0.0.1"}},
+			expectedClientIP: "10.0.0.1",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "X-Real-IP",
+			config:           httprouter.ProxyHeadersConfig{Headers: []string{httprouter.HeaderXRealIP}},
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"X-Real-Ip": {"198.51.100.7"}},
+			expectedClientIP: "198.51.100.7",
+			expectedHost:     "example.com",
+		},
+		{
+			name:       "spoofed Forwarded header passed through by a proxy setting X-Forwarded-For",
+			remoteAddr: "10.0.0.2:1234",
+			headers: map[string][]string{
+				"Forwarded":       {"for=1.1.1.1;host=evil.example.com"},
+				"X-Forwarded-For": {"198.51.100.1"},
+				"X-Real-Ip":       {"1.1.1.1"},
+			},
+			expectedClientIP: "198.51.100.1",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "header not configured",
+			config:           httprouter.ProxyHeadersConfig{Headers: []string{httprouter.HeaderXRealIP}},
+			remoteAddr:       "10.0.0.2:1234",
+			headers:          map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expectedClientIP: "10.0.0.2",
+			expectedHost:     "example.com",
+		},
+		{
+			name:             "trusted single IPv6 proxy",
+			remoteAddr:       "[2001:db8::1]:443",
+			headers:          map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expectedClientIP: "198.51.100.1",
+			expectedHost:     "example.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := testCase.config
+			config.TrustedProxies = []string{"10.0.0.0/8", "2001:db8::1"}
+
+			var clientIP, scheme, host string
+
+			router := httprouter.New()
+			router.Pre(httprouter.ProxyHeaders(config))
+			router.Get("/", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				clientIP = httprouter.ClientIPFromContext(request.Context())
+				scheme = request.URL.Scheme
+				host = request.Host
+
+				return nil
+			}), "")
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = testCase.remoteAddr
+			request.Header = testCase.headers
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			assert.Equal(t, testCase.expectedClientIP, clientIP)
+			assert.Equal(t, testCase.expectedScheme, scheme)
+			assert.Equal(t, testCase.expectedHost, host)
+		})
+	}
+}
+
+func TestProxyHeaders_AccessLogAndRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	router := httprouter.New()
+	router.Pre(
+		httprouter.AccessLog(httprouter.AccessLogConfig{Format: httprouter.AccessLogCommon, Output: &logs}),
+		httprouter.ProxyHeaders(httprouter.ProxyHeadersConfig{TrustedProxies: []string{"10.0.0.1"}}),
+		httprouter.RateLimit(httprouter.RateLimitConfig{Limit: 1}),
+	)
+	router.Get("/", &mockHandler{}, "")
+
+	serve := func(clientIP string) int {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = "10.0.0.1:1234"
+		request.Header.Set("X-Forwarded-For", clientIP)
+
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+
+		return responseRecorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("198.51.100.1"))
+	assert.Equal(t, http.StatusOK, serve("198.51.100.2"), "clients behind the same proxy have their own quota")
+	assert.Equal(t, http.StatusTooManyRequests, serve("198.51.100.1"))
+
+	assert.Contains(t, logs.String(), "198.51.100.2 - - [")
+	assert.NotContains(t, logs.String(), "10.0.0.1")
+}
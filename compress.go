@@ -0,0 +1,266 @@
+package httprouter
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultSkipContentTypes are content types that are already compressed. An entry that ends with a slash
+// matches every subtype.
+var DefaultSkipContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"video/",
+	"audio/",
+	"font/woff",
+	"font/woff2",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+	"application/octet-stream",
+}
+
+type CompressConfig struct {
+	// Level is passed to the encoders, 0 is their default level.
+	Level int
+	// MinSize is the number of bytes a response needs to be compressed, 1024 is used when it is zero.
+	// Flushed responses are compressed regardless of their size.
+	MinSize int
+	// SkipContentTypes are never compressed, DefaultSkipContentTypes are used when it is nil.
+	SkipContentTypes []string
+	// Encoders are the negotiated content codings, NewEncoderRegistry() is used when it is nil.
+	Encoders *EncoderRegistry
+}
+
+// Compress compresses responses with the content coding negotiated from the Accept-Encoding header and adds
+// Accept-Encoding to the Vary header. Responses that are smaller than MinSize, have a skipped content type or are
+// already encoded are written as is. Apply it to a group with Use.
+func Compress(config CompressConfig) MiddlewareFunc {
+	if config.MinSize == 0 {
+		config.MinSize = 1024
+	}
+
+	if config.SkipContentTypes == nil {
+		config.SkipContentTypes = DefaultSkipContentTypes
+	}
+
+	if config.Encoders == nil {
+		config.Encoders = NewEncoderRegistry()
+	}
+
+	encodings := config.Encoders.Encodings()
+	pools := make(map[string]*sync.Pool, len(encodings))
+
+	for _, encoding := range encodings {
+		newEncoder := config.Encoders.encoders[encoding]
+
+		// the first encoder checks the level, so an invalid one fails here and not on the first request
+		encoder, err := newEncoder(io.Discard, config.Level)
+		if err != nil {
+			panic("httprouter: " + encoding + " encoder: " + err.Error())
+		}
+
+		pools[encoding] = &sync.Pool{
+			New: func() any {
+				encoder, _ := newEncoder(io.Discard, config.Level)
+
+				return encoder
+			},
+		}
+		pools[encoding].Put(encoder)
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			responseWriter.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(request.Header.Get("Accept-Encoding"), encodings)
+			if encoding == "" || request.Method == http.MethodHead {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			writer := &compressWriter{
+				ResponseWriter: responseWriter,
+				config:         &config,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				status:         http.StatusOK,
+			}
+
+			err := next.Handle(writer, request)
+
+			writer.close()
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+// compressWriter buffers the beginning of a response until it knows whether to compress it.
+type compressWriter struct {
+	http.ResponseWriter
+
+	config   *CompressConfig
+	encoding string
+	pool     *sync.Pool
+	encoder  Encoder
+
+	status      int
+	wroteHeader bool
+	buf         []byte
+	decided     bool
+}
+
+func (w *compressWriter) WriteHeader(statusCode int) {
+	if w.decided || w.wroteHeader {
+		return
+	}
+
+	// informational responses are sent right away, they do not carry the body
+	if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(statusCode)
+
+		return
+	}
+
+	w.status = statusCode
+	w.wroteHeader = true
+
+	if !bodyAllowed(statusCode) || w.Header().Get("Content-Encoding") != "" || w.Header().Get("Content-Range") != "" {
+		w.decide(false)
+
+		return
+	}
+
+	if contentLength, err := strconv.Atoi(w.Header().Get("Content-Length")); err == nil && contentLength < w.config.MinSize {
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.config.MinSize {
+		w.decide(w.compressible())
+	}
+
+	return len(data), nil
+}
+
+// Flush decides on compression regardless of the response size, so a streamed response is compressed from the start.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		w.decide(w.compressible())
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(data) //nolint:wrapcheck
+	}
+
+	return w.ResponseWriter.Write(data) //nolint:wrapcheck
+}
+
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		// set the sniffed type now, net/http can't sniff compressed data
+		contentType = http.DetectContentType(w.buf)
+		header.Set("Content-Type", contentType)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, skipped := range w.config.SkipContentTypes {
+		if mediaType == skipped || (strings.HasSuffix(skipped, "/") && strings.HasPrefix(mediaType, skipped)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decide writes the header and the buffered data, compressed or as is.
+func (w *compressWriter) decide(compress bool) {
+	w.decided = true
+
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		// the compressed representation is not byte for byte equal to the uncompressed one
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		w.encoder = w.pool.Get().(Encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if len(w.buf) != 0 {
+		_, _ = w.write(w.buf)
+		w.buf = nil
+	}
+}
+
+// close finishes the response, a response that was never written is left untouched for the error handler.
+func (w *compressWriter) close() {
+	if !w.decided && w.wroteHeader {
+		w.decide(false)
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		w.pool.Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+func bodyAllowed(statusCode int) bool {
+	return statusCode != http.StatusNoContent && statusCode != http.StatusNotModified
+}
@@ -0,0 +1,261 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperEncoder is a fake content coding that upper cases the data.
+type upperEncoder struct {
+	w io.Writer
+}
+
+func (e *upperEncoder) Write(data []byte) (int, error) {
+	return e.w.Write(bytes.ToUpper(data)) //nolint:wrapcheck
+}
+
+func (e *upperEncoder) Close() error      { return nil }
+func (e *upperEncoder) Flush() error      { return nil }
+func (e *upperEncoder) Reset(w io.Writer) { e.w = w }
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var reader io.Reader
+
+	switch encoding {
+	case "gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+
+		reader = gzipReader
+	case "deflate":
+		zlibReader, err := zlib.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+
+		reader = zlibReader
+	case "x-upper":
+		return strings.ToLower(string(body))
+	default:
+		return string(body)
+	}
+
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(decoded)
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{name: "gzip", acceptEncoding: "gzip", expectedEncoding: "gzip"},
+		{name: "deflate", acceptEncoding: "deflate", expectedEncoding: "deflate"},
+		{name: "q-values", acceptEncoding: "gzip;q=0.5, deflate", expectedEncoding: "deflate"},
+		{name: "preference on equal q-values", acceptEncoding: "deflate, gzip", expectedEncoding: "gzip"},
+		{name: "registered encoder is preferred", acceptEncoding: "gzip, x-upper", expectedEncoding: "x-upper"},
+		{name: "wildcard", acceptEncoding: "*", expectedEncoding: "x-upper"},
+		{name: "wildcard with excluded codings", acceptEncoding: "x-upper;q=0, gzip;q=0, *;q=0.1", expectedEncoding: "deflate"},
+		{name: "identity", acceptEncoding: "identity", expectedEncoding: ""},
+		{name: "no header", acceptEncoding: "", expectedEncoding: ""},
+	}
+
+	body := strings.Repeat("compress me ", 200)
+
+	encoders := httprouter.NewEncoderRegistry()
+	encoders.Register("x-upper", func(w io.Writer, _ int) (httprouter.Encoder, error) {
+		return &upperEncoder{w: w}, nil
+	})
+
+	router := httprouter.New()
+	router.Use(httprouter.Compress(httprouter.CompressConfig{Encoders: encoders}))
+	router.Get("/text", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		responseWriter.Header().Set("Content-Length", "2400")
+		_, _ = responseWriter.Write([]byte(body))
+
+		return nil
+	}), "")
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, "/text", nil)
+			request.Header.Set("Accept-Encoding", testCase.acceptEncoding)
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedEncoding, responseRecorder.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", responseRecorder.Header().Get("Vary"))
+			assert.Equal(t, "text/plain; charset=utf-8", responseRecorder.Header().Get("Content-Type"))
+			assert.Equal(t, body, decodeBody(t, testCase.expectedEncoding, responseRecorder.Body.Bytes()))
+
+			if testCase.expectedEncoding != "" {
+				assert.Empty(t, responseRecorder.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestCompress_Skips(t *testing.T) {
+	t.Parallel()
+
+	largeBody := strings.Repeat("a", 2048)
+
+	testCases := []struct {
+		name             string
+		handler          httprouter.Handler
+		expectedStatus   int
+		expectedEncoding string
+		expectedBody     string
+		expectedETag     string
+	}{
+		{
+			name: "large response",
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("ETag", `"v1"`)
+				responseWriter.WriteHeader(http.StatusCreated)
+				_, _ = responseWriter.Write([]byte(largeBody[:1000]))
+				_, _ = responseWriter.Write([]byte(largeBody[1000:]))
+
+				return nil
+			}),
+			expectedStatus:   http.StatusCreated,
+			expectedEncoding: "gzip",
+			expectedBody:     largeBody,
+			expectedETag:     `W/"v1"`,
+		},
+		{
+			name: "small response",
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("ETag", `"v1"`)
+				_, _ = responseWriter.Write([]byte("small"))
+
+				return nil
+			}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "small",
+			expectedETag:   `"v1"`,
+		},
+		{
+			name: "compressed content type",
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("Content-Type", "image/png")
+				_, _ = responseWriter.Write([]byte(largeBody))
+
+				return nil
+			}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   largeBody,
+		},
+		{
+			name: "encoded response",
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("Content-Encoding", "br")
+				_, _ = responseWriter.Write([]byte(largeBody))
+
+				return nil
+			}),
+			expectedStatus:   http.StatusOK,
+			expectedEncoding: "br",
+			expectedBody:     largeBody,
+		},
+		{
+			name: "no content",
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.WriteHeader(http.StatusNoContent)
+
+				return nil
+			}),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "handler error",
+			handler:        &mockHandler{errToReturn: httprouter.NewHTTPError(http.StatusBadRequest, errors.New("bad"))},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad Request\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Use(httprouter.Compress(httprouter.CompressConfig{}))
+			router.Get("/", testCase.handler, "")
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("Accept-Encoding", "gzip")
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedEncoding, responseRecorder.Header().Get("Content-Encoding"))
+			assert.Equal(t, testCase.expectedETag, responseRecorder.Header().Get("ETag"))
+			assert.Equal(t, testCase.expectedBody, decodeBody(t, testCase.expectedEncoding, responseRecorder.Body.Bytes()))
+		})
+	}
+}
+
+func TestCompress_Flush(t *testing.T) {
+	t.Parallel()
+
+	var flushedBody []byte
+
+	responseRecorder := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Use(httprouter.Compress(httprouter.CompressConfig{}))
+	router.Get("/events", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		responseWriter.Header().Set("Content-Type", "text/event-stream")
+		_, _ = responseWriter.Write([]byte("data: 1\n\n"))
+
+		require.NoError(t, http.NewResponseController(responseWriter).Flush())
+
+		flushedBody = bytes.Clone(responseRecorder.Body.Bytes())
+
+		_, _ = responseWriter.Write([]byte("data: 2\n\n"))
+
+		return nil
+	}), "")
+
+	request := httptest.NewRequest(http.MethodGet, "/events", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	router.ServeHTTP(responseRecorder, request)
+
+	assert.True(t, responseRecorder.Flushed)
+	assert.Equal(t, "gzip", responseRecorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", decodeBody(t, "gzip", responseRecorder.Body.Bytes()))
+
+	// the first event is readable before the response ends
+	gzipReader, err := gzip.NewReader(bytes.NewReader(flushedBody))
+	require.NoError(t, err)
+
+	flushedEvent, err := io.ReadAll(gzipReader)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "data: 1\n\n", string(flushedEvent))
+}
@@ -0,0 +1,210 @@
+package httprouter
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins are exact origins, e.g. "https://example.com", origins with a wildcard, e.g. "https://*.example.com",
+	// or "*" that allows every origin.
+	AllowedOrigins []string
+	// AllowedOriginPatterns allow origins that match any of the regular expressions.
+	AllowedOriginPatterns []*regexp.Regexp
+	// AllowOriginFunc allows origins it returns true for.
+	AllowOriginFunc func(origin string, request *http.Request) bool
+	// AllowedHeaders are request headers preflights are approved for, every requested header is approved
+	// when it is empty or contains "*".
+	AllowedHeaders []string
+	// ExposedHeaders are response headers the browser lets scripts read.
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies and credentials, the request origin is echoed back instead of "*".
+	AllowCredentials bool
+	// MaxAge is the number of seconds browsers may cache a preflight response, 0 omits the header.
+	MaxAge int
+	// AllowPrivateNetwork approves preflights of public sites that request access to the private network.
+	AllowPrivateNetwork bool
+}
+
+// CORS answers CORS preflight requests and adds CORS headers to the responses of allowed origins.
+// The methods of a preflight response are the methods registered for the request path, so no OPTIONS routes
+// are needed. Add it with Router.Pre to apply one policy to every route, or with Use to a group to apply
+// a policy to the group routes: the router passes preflights of routes without an OPTIONS handler through
+// the scope middleware of the route registered for the requested method, preflights of routes outside the scope
+// are answered with 405 Method Not Allowed.
+func CORS(config CORSConfig) MiddlewareFunc {
+	policy := newCORSPolicy(config)
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			origin := request.Header.Get("Origin")
+			if origin == "" {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			header := responseWriter.Header()
+
+			if isPreflightRequest(request) {
+				header.Add("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers")
+				policy.preflight(header, request, origin)
+				responseWriter.WriteHeader(http.StatusNoContent)
+
+				return nil
+			}
+
+			header.Add("Vary", "Origin")
+
+			if policy.allowsOrigin(origin, request) {
+				policy.setOrigin(header, origin)
+
+				if len(config.ExposedHeaders) != 0 {
+					header.Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+				}
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+type corsPolicy struct {
+	CORSConfig
+
+	allowAllOrigins  bool
+	allowAllHeaders  bool
+	exactOrigins     map[string]struct{}
+	wildcardOrigins  [][2]string
+	allowedHeaderSet map[string]struct{}
+}
+
+func newCORSPolicy(config CORSConfig) *corsPolicy {
+	policy := &corsPolicy{
+		CORSConfig:       config,
+		allowAllHeaders:  len(config.AllowedHeaders) == 0,
+		exactOrigins:     make(map[string]struct{}),
+		allowedHeaderSet: make(map[string]struct{}),
+	}
+
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(origin)
+
+		switch prefix, suffix, found := strings.Cut(origin, "*"); {
+		case origin == "*":
+			policy.allowAllOrigins = true
+		case found:
+			policy.wildcardOrigins = append(policy.wildcardOrigins, [2]string{prefix, suffix})
+		default:
+			policy.exactOrigins[origin] = struct{}{}
+		}
+	}
+
+	for _, header := range config.AllowedHeaders {
+		if header == "*" {
+			policy.allowAllHeaders = true
+		}
+
+		policy.allowedHeaderSet[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	return policy
+}
+
+func (p *corsPolicy) allowsOrigin(origin string, request *http.Request) bool {
+	if p.allowAllOrigins {
+		return true
+	}
+
+	lowerOrigin := strings.ToLower(origin)
+
+	if _, ok := p.exactOrigins[lowerOrigin]; ok {
+		return true
+	}
+
+	for _, wildcard := range p.wildcardOrigins {
+		prefix, suffix := wildcard[0], wildcard[1]
+		if len(lowerOrigin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(lowerOrigin, prefix) && strings.HasSuffix(lowerOrigin, suffix) {
+			return true
+		}
+	}
+
+	for _, pattern := range p.AllowedOriginPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+
+	return p.AllowOriginFunc != nil && p.AllowOriginFunc(origin, request)
+}
+
+func (p *corsPolicy) setOrigin(header http.Header, origin string) {
+	if p.allowAllOrigins && !p.AllowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// preflight sets the headers that approve the preflight, no CORS headers are set if it is not approved.
+func (p *corsPolicy) preflight(header http.Header, request *http.Request, origin string) {
+	if !p.allowsOrigin(origin, request) {
+		return
+	}
+
+	methods := AllowedMethods(request)
+	if !contains(methods, request.Header.Get("Access-Control-Request-Method")) {
+		return
+	}
+
+	requestedHeaders := request.Header.Values("Access-Control-Request-Headers")
+	if !p.allowsHeaders(requestedHeaders) {
+		return
+	}
+
+	privateNetwork := request.Header.Get("Access-Control-Request-Private-Network") == "true"
+	if privateNetwork && !p.AllowPrivateNetwork {
+		return
+	}
+
+	p.setOrigin(header, origin)
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	if len(requestedHeaders) != 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ", "))
+	}
+
+	if p.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(p.MaxAge))
+	}
+
+	if privateNetwork {
+		header.Set("Access-Control-Allow-Private-Network", "true")
+	}
+}
+
+func (p *corsPolicy) allowsHeaders(requestedHeaders []string) bool {
+	if p.allowAllHeaders {
+		return true
+	}
+
+	for _, value := range requestedHeaders {
+		for _, header := range strings.Split(value, ",") {
+			header = strings.TrimSpace(header)
+			if header == "" {
+				continue
+			}
+
+			if _, ok := p.allowedHeaderSet[http.CanonicalHeaderKey(header)]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,331 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS_Pre(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		config          httprouter.CORSConfig
+		method          string
+		path            string
+		requestHeaders  map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:   "preflight answered with the registered methods",
+			config: httprouter.CORSConfig{AllowedOrigins: []string{"https://example.com"}, MaxAge: 600},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "content-type, x-token",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "content-type, x-token",
+				"Access-Control-Max-Age":       "600",
+				"Vary":                         "Origin, Access-Control-Request-Method, Access-Control-Request-Headers",
+			},
+		},
+		{
+			name:   "preflight for a method without route",
+			config: httprouter.CORSConfig{AllowedOrigins: []string{"*"}},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:   "preflight from a disallowed origin",
+			config: httprouter.CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                        "https://evil.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name: "preflight with a disallowed header",
+			config: httprouter.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedHeaders: []string{"Content-Type"},
+			},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "content-type, x-token",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name: "preflight requesting private network access",
+			config: httprouter.CORSConfig{
+				AllowedOrigins:      []string{"https://*.example.com"},
+				AllowPrivateNetwork: true,
+			},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                                 "https://app.example.com",
+				"Access-Control-Request-Method":          http.MethodGet,
+				"Access-Control-Request-Private-Network": "true",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":          "https://app.example.com",
+				"Access-Control-Allow-Private-Network": "true",
+			},
+		},
+		{
+			name:   "preflight requesting private network access without approval",
+			config: httprouter.CORSConfig{AllowedOrigins: []string{"*"}},
+			method: http.MethodOptions,
+			path:   "/users",
+			requestHeaders: map[string]string{
+				"Origin":                                 "https://app.example.com",
+				"Access-Control-Request-Method":          http.MethodGet,
+				"Access-Control-Request-Private-Network": "true",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name: "actual request with credentials",
+			config: httprouter.CORSConfig{
+				AllowedOrigins:   []string{"*"},
+				AllowCredentials: true,
+				ExposedHeaders:   []string{"X-Total", "X-Page"},
+			},
+			method:         http.MethodGet,
+			path:           "/users",
+			requestHeaders: map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Total, X-Page",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:           "actual request from any origin",
+			config:         httprouter.CORSConfig{AllowedOrigins: []string{"*"}},
+			method:         http.MethodGet,
+			path:           "/users",
+			requestHeaders: map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "*",
+			},
+		},
+		{
+			name:           "actual request from an origin matching a pattern",
+			config:         httprouter.CORSConfig{AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^http://localhost:\d+$`)}},
+			method:         http.MethodPost,
+			path:           "/users",
+			requestHeaders: map[string]string{"Origin": "http://localhost:3000"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "http://localhost:3000",
+			},
+		},
+		{
+			name: "actual request from an origin allowed by func",
+			config: httprouter.CORSConfig{AllowOriginFunc: func(origin string, _ *http.Request) bool {
+				return strings.HasSuffix(origin, ".internal")
+			}},
+			method:         http.MethodGet,
+			path:           "/users",
+			requestHeaders: map[string]string{"Origin": "https://admin.internal"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "https://admin.internal",
+			},
+		},
+		{
+			name:           "actual request from a disallowed origin",
+			config:         httprouter.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:         http.MethodGet,
+			path:           "/users",
+			requestHeaders: map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:           "request without origin",
+			config:         httprouter.CORSConfig{AllowedOrigins: []string{"*"}},
+			method:         http.MethodGet,
+			path:           "/users",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Pre(httprouter.CORS(testCase.config))
+			router.Get("/users", &mockHandler{}, "")
+			router.Post("/users", &mockHandler{}, "")
+
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			for headerName, headerValue := range testCase.requestHeaders {
+				request.Header.Set(headerName, headerValue)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+
+			for headerName, headerValue := range testCase.expectedHeaders {
+				assert.Equal(t, headerValue, responseRecorder.Header().Get(headerName), headerName)
+			}
+		})
+	}
+}
+
+func TestCORS_Group(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		path                string
+		requestMethod       string
+		expectedStatus      int
+		expectedAllowOrigin string
+		expectedAllow       string
+	}{
+		{
+			name:                "preflight of a group route",
+			path:                "/api/users",
+			requestMethod:       http.MethodPut,
+			expectedStatus:      http.StatusNoContent,
+			expectedAllowOrigin: "https://example.com",
+		},
+		{
+			name:           "preflight of a route outside the group",
+			path:           "/public",
+			requestMethod:  http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "preflight for a method without route",
+			path:           "/api/users",
+			requestMethod:  http.MethodDelete,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Get("/public", &mockHandler{}, "")
+			router.Route("/api", func(r httprouter.Router) {
+				r.Use(httprouter.CORS(httprouter.CORSConfig{AllowedOrigins: []string{"https://example.com"}}))
+				r.Get("/users", &mockHandler{}, "")
+				r.Put("/users", &mockHandler{}, "")
+			})
+
+			request := httptest.NewRequest(http.MethodOptions, testCase.path, nil)
+			request.Header.Set("Origin", "https://example.com")
+			request.Header.Set("Access-Control-Request-Method", testCase.requestMethod)
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedAllowOrigin, responseRecorder.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, testCase.expectedAllow, responseRecorder.Header().Get("Allow"))
+
+			if testCase.expectedAllowOrigin != "" {
+				assert.Equal(t, "GET, PUT", responseRecorder.Header().Get("Access-Control-Allow-Methods"))
+			}
+		})
+	}
+}
+
+func TestRouter_PreflightWithoutCORS(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Get("/users", &mockHandler{}, "")
+
+	request := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	request.Header.Set("Origin", "https://example.com")
+	request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, responseRecorder.Code)
+	assert.Empty(t, responseRecorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestAllowedMethods(t *testing.T) {
+	t.Parallel()
+
+	var allowedMethods []string
+
+	router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	router.Get("/users/:id", &mockHandler{}, "")
+	router.Delete("/users/:id", &mockHandler{}, "")
+	router.Options("/users/:id", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+		allowedMethods = httprouter.AllowedMethods(request)
+
+		return nil
+	}), "")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/users/1", nil))
+
+	assert.Equal(t, []string{http.MethodGet, http.MethodDelete, http.MethodOptions}, allowedMethods)
+	assert.Nil(t, httprouter.AllowedMethods(httptest.NewRequest(http.MethodGet, "/users/1", nil)))
+}
@@ -0,0 +1,294 @@
+package httprouter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCSRFHeader     = "X-CSRF-Token"
+	DefaultCSRFField      = "csrf_token"
+	DefaultCSRFCookieName = "_csrf"
+)
+
+const csrfTokenLength = 32
+
+// CSRFStore keeps the CSRF token of the client. The cookie store of CSRFCookieStore implements the double-submit
+// cookie pattern, a store keeping the token in the server-side session implements the synchronizer token pattern.
+type CSRFStore interface {
+	// Load returns the token of the client, it is empty when the client has none.
+	Load(request *http.Request) (string, error)
+	// Save stores the new token of the client.
+	Save(responseWriter http.ResponseWriter, request *http.Request, token string) error
+}
+
+type CSRFCookieStore struct {
+	// Name of the cookie, DefaultCSRFCookieName is used when it is empty.
+	Name   string
+	Domain string
+	// Path of the cookie, "/" is used when it is empty.
+	Path string
+	// MaxAge of the cookie, the cookie lasts for the browser session when it is zero.
+	MaxAge time.Duration
+	// Secure should be set when the site is served over HTTPS.
+	Secure bool
+	// SameSite of the cookie, http.SameSiteLaxMode is used when it is zero.
+	SameSite http.SameSite
+}
+
+// Load returns the token stored in the cookie.
+func (s CSRFCookieStore) Load(request *http.Request) (string, error) {
+	cookie, err := request.Cookie(s.name())
+	if err != nil {
+		return "", nil //nolint:nilerr
+	}
+
+	return cookie.Value, nil
+}
+
+// Save sets the cookie holding the token, the cookie is HttpOnly as the token is sent back from CSRFToken.
+func (s CSRFCookieStore) Save(responseWriter http.ResponseWriter, _ *http.Request, token string) error {
+	cookie := &http.Cookie{
+		Name:     s.name(),
+		Value:    token,
+		Domain:   s.Domain,
+		Path:     s.Path,
+		MaxAge:   int(s.MaxAge.Seconds()),
+		Secure:   s.Secure,
+		HttpOnly: true,
+		SameSite: s.SameSite,
+	}
+
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(responseWriter, cookie)
+
+	return nil
+}
+
+func (s CSRFCookieStore) name() string {
+	if s.Name == "" {
+		return DefaultCSRFCookieName
+	}
+
+	return s.Name
+}
+
+type CSRFConfig struct {
+	// Store keeps the token of the client, CSRFCookieStore{} is used when it is nil.
+	Store CSRFStore
+	// Header and Field are the request header and the form field the token is read from, in this order.
+	// DefaultCSRFHeader and DefaultCSRFField are used when they are empty.
+	Header string
+	Field  string
+	// TrustedOrigins are the origins other than the origin of the request allowed to send unsafe requests,
+	// e.g. "https://admin.example.com".
+	TrustedOrigins []string
+	// ExemptRoutes are the names of the routes that are not protected, use CSRFExempt to exempt a group.
+	ExemptRoutes []string
+}
+
+type csrfState struct {
+	token string
+	field string
+}
+
+type csrfExemptMeta struct{}
+
+// CSRFExempt returns a router value whose routes are not protected by the CSRF middleware, e.g. for webhooks
+// authenticated by other means. Groups created from it inherit the exemption.
+func CSRFExempt(r Router) Router {
+	return WithMeta(r, csrfExemptMeta{})
+}
+
+// CSRF protects the routes against cross-site request forgery. Requests with an unsafe method must come
+// from the origin of the request or a trusted origin, according to the Sec-Fetch-Site, Origin or Referer header,
+// and must send the token of the client in the header or the form field. Other requests are answered with
+// 403 Forbidden by the router error handler. CSRF reads the exemptions from the matched route, so it must be added
+// with Use or With rather than Pre.
+func CSRF(config CSRFConfig) MiddlewareFunc {
+	if config.Store == nil {
+		config.Store = CSRFCookieStore{}
+	}
+
+	if config.Header == "" {
+		config.Header = DefaultCSRFHeader
+	}
+
+	if config.Field == "" {
+		config.Field = DefaultCSRFField
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			token, err := config.Store.Load(request)
+			if err != nil {
+				return fmt.Errorf("httprouter: load CSRF token: %w", err)
+			}
+
+			if len(unmaskCSRFToken(token)) != csrfTokenLength {
+				token = newCSRFToken()
+
+				if err := config.Store.Save(responseWriter, request, token); err != nil {
+					return fmt.Errorf("httprouter: save CSRF token: %w", err)
+				}
+			}
+
+			responseWriter.Header().Add("Vary", "Cookie")
+
+			request = request.WithContext(context.WithValue(request.Context(), csrfKey, csrfState{
+				token: token,
+				field: config.Field,
+			}))
+
+			if isSafeMethod(request.Method) || csrfExempt(config, request) {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			if !sameOriginRequest(request, config.TrustedOrigins) {
+				return NewHTTPError(http.StatusForbidden, ErrCrossOriginRequest)
+			}
+
+			sent := request.Header.Get(config.Header)
+			if sent == "" {
+				sent = request.PostFormValue(config.Field)
+			}
+
+			expected := unmaskCSRFToken(token)
+			if subtle.ConstantTimeCompare(unmaskCSRFToken(sent), expected) != 1 {
+				return NewHTTPError(http.StatusForbidden, ErrInvalidCSRFToken)
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+// CSRFToken returns the token to send back with unsafe requests, in the header or the form field of the CSRF
+// middleware. The token is masked differently on each call, so it does not leak through compressed responses.
+func CSRFToken(ctx context.Context) string {
+	state, ok := ctx.Value(csrfKey).(csrfState)
+	if !ok {
+		return ""
+	}
+
+	return maskCSRFToken(unmaskCSRFToken(state.token))
+}
+
+// CSRFTemplateField returns the hidden form input holding the token, to be used in html/template forms.
+func CSRFTemplateField(ctx context.Context) template.HTML {
+	state, ok := ctx.Value(csrfKey).(csrfState)
+	if !ok {
+		return ""
+	}
+
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, //nolint:gosec
+		template.HTMLEscapeString(state.field), CSRFToken(ctx)))
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func csrfExempt(config CSRFConfig, request *http.Request) bool {
+	routeInfo := CurrentRoute(request.Context())
+
+	if _, ok := Meta[csrfExemptMeta](routeInfo); ok {
+		return true
+	}
+
+	return routeInfo.Name != "" && slices.Contains(config.ExemptRoutes, routeInfo.Name)
+}
+
+// sameOriginRequest tells whether the request comes from its own origin or a trusted one. Requests with neither
+// Sec-Fetch-Site, Origin nor Referer header are not sent by browsers and are left to the token check.
+func sameOriginRequest(request *http.Request, trustedOrigins []string) bool {
+	origin := request.Header.Get("Origin")
+
+	switch request.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return origin != "" && slices.Contains(trustedOrigins, origin)
+	}
+
+	if origin == "" {
+		referer, err := url.Parse(request.Referer())
+		if err != nil || referer.Host == "" {
+			return request.Referer() == ""
+		}
+
+		origin = referer.Scheme + "://" + referer.Host
+	}
+
+	if slices.Contains(trustedOrigins, origin) {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+
+	return err == nil && originURL.Host != "" && strings.EqualFold(originURL.Host, request.Host)
+}
+
+func newCSRFToken() string {
+	token := make([]byte, csrfTokenLength)
+
+	_, _ = rand.Read(token)
+
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// maskCSRFToken returns a random one-time pad followed by the token XOR-ed with it.
+func maskCSRFToken(token []byte) string {
+	masked := make([]byte, 2*len(token))
+
+	_, _ = rand.Read(masked[:len(token)])
+
+	for i := range token {
+		masked[len(token)+i] = masked[i] ^ token[i]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+// unmaskCSRFToken decodes a token as stored, or as returned by maskCSRFToken. It returns nil for invalid tokens.
+func unmaskCSRFToken(token string) []byte {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil
+	}
+
+	switch len(data) {
+	case csrfTokenLength:
+		return data
+	case 2 * csrfTokenLength:
+		for i := range csrfTokenLength {
+			data[csrfTokenLength+i] ^= data[i]
+		}
+
+		return data[csrfTokenLength:]
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionCSRFStore keeps the tokens server-side by session cookie, as in the synchronizer token pattern.
+type sessionCSRFStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (s *sessionCSRFStore) Load(request *http.Request) (string, error) {
+	cookie, err := request.Cookie("session")
+	if err != nil {
+		return "", nil //nolint:nilerr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[cookie.Value], nil
+}
+
+func (s *sessionCSRFStore) Save(_ http.ResponseWriter, request *http.Request, token string) error {
+	cookie, err := request.Cookie("session")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[cookie.Value] = token
+
+	return nil
+}
+
+func newCSRFRouter(config httprouter.CSRFConfig) httprouter.Router {
+	router := httprouter.New()
+	router.Use(httprouter.CSRF(config))
+
+	router.Get("/form", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+		_, _ = responseWriter.Write([]byte(httprouter.CSRFTemplateField(request.Context())))
+
+		return nil
+	}), "form")
+	router.Post("/form", &mockHandler{}, "submit")
+	router.Post("/login", &mockHandler{}, "login")
+	httprouter.CSRFExempt(router).Route("/webhooks", func(webhooks httprouter.Router) {
+		webhooks.Post("/payments", &mockHandler{}, "payments-webhook")
+	})
+
+	return router
+}
+
+var csrfFieldPattern = regexp.MustCompile(`^<input type="hidden" name="csrf_token" value="([A-Za-z0-9_-]+)">$`)
+
+// csrfForm gets the form, it returns the cookies set and the token of the hidden field.
+func csrfForm(t *testing.T, router http.Handler, cookies ...*http.Cookie) ([]*http.Cookie, string) {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodGet, "/form", nil)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	match := csrfFieldPattern.FindStringSubmatch(responseRecorder.Body.String())
+	require.Len(t, match, 2)
+
+	return responseRecorder.Result().Cookies(), match[1]
+}
+
+//nolint:funlen
+func TestCSRF(t *testing.T) {
+	t.Parallel()
+
+	router := newCSRFRouter(httprouter.CSRFConfig{
+		ExemptRoutes:   []string{"login"},
+		TrustedOrigins: []string{"https://admin.example.com"},
+	})
+
+	cookies, token := csrfForm(t, router)
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "_csrf", cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+
+	_, otherToken := csrfForm(t, router, cookies...)
+	assert.NotEqual(t, token, otherToken, "tokens are masked differently on each call")
+
+	testCases := []struct {
+		name           string
+		path           string
+		headers        map[string]string
+		form           url.Values
+		noCookie       bool
+		expectedStatus int
+	}{
+		{
+			name:           "token in the header",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token in the form field",
+			path:           "/form",
+			form:           url.Values{"csrf_token": {otherToken}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			path:           "/form",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token without the cookie",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token},
+			noCookie:       true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid token",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": strings.Repeat("A", len(token))},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "same origin",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Origin": "http://example.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cross origin",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Origin": "https://evil.example.org"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "trusted origin",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Origin": "https://admin.example.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cross-site fetch",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Sec-Fetch-Site": "cross-site"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "same-origin fetch",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Sec-Fetch-Site": "same-origin"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cross origin referer",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Referer": "https://evil.example.org/page"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "same origin referer",
+			path:           "/form",
+			headers:        map[string]string{"X-CSRF-Token": token, "Referer": "http://example.com/form"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "route exempted by name",
+			path:           "/login",
+			headers:        map[string]string{"Origin": "https://evil.example.org"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "route of an exempted group",
+			path:           "/webhooks/payments",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodPost, testCase.path, strings.NewReader(testCase.form.Encode()))
+			if testCase.form != nil {
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+
+			for name, value := range testCase.headers {
+				request.Header.Set(name, value)
+			}
+
+			if !testCase.noCookie {
+				request.AddCookie(cookies[0])
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+		})
+	}
+}
+
+func TestCSRF_SynchronizerToken(t *testing.T) {
+	t.Parallel()
+
+	store := &sessionCSRFStore{tokens: map[string]string{}}
+	router := newCSRFRouter(httprouter.CSRFConfig{Store: store})
+
+	session := &http.Cookie{Name: "session", Value: "session-1"}
+
+	cookies, token := csrfForm(t, router, session)
+	assert.Empty(t, cookies)
+	assert.Len(t, store.tokens, 1)
+
+	post := func(session *http.Cookie, token string) int {
+		request := httptest.NewRequest(http.MethodPost, "/form", nil)
+		request.Header.Set("X-CSRF-Token", token)
+		request.AddCookie(session)
+
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+
+		return responseRecorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post(session, token))
+
+	_, otherToken := csrfForm(t, router, &http.Cookie{Name: "session", Value: "session-2"})
+	assert.Equal(t, http.StatusForbidden, post(session, otherToken), "token of another session")
+}
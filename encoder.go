@@ -0,0 +1,126 @@
+package httprouter
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Encoder compresses the data written to it into the writer it was created or reset with,
+// *gzip.Writer, *zlib.Writer and *flate.Writer are encoders.
+type Encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// EncoderFunc creates an encoder that writes to w, level 0 is the default level of the encoder.
+type EncoderFunc func(w io.Writer, level int) (Encoder, error)
+
+// EncoderRegistry holds the content codings the Compress middleware negotiates.
+type EncoderRegistry struct {
+	// encodings are ordered from the most to the least preferred one
+	encodings []string
+	encoders  map[string]EncoderFunc
+}
+
+// NewEncoderRegistry returns a registry with the gzip and deflate content codings, gzip is preferred.
+func NewEncoderRegistry() *EncoderRegistry {
+	registry := &EncoderRegistry{encoders: make(map[string]EncoderFunc)}
+
+	registry.Register("deflate", newDeflateEncoder)
+	registry.Register("gzip", newGzipEncoder)
+
+	return registry
+}
+
+// Register adds the content coding, e.g. "br" or "zstd", or replaces its encoder. Encodings registered later are
+// preferred when a client accepts several of them with the same q-value.
+func (r *EncoderRegistry) Register(encoding string, encoder EncoderFunc) {
+	encoding = strings.ToLower(encoding)
+
+	r.encodings = slices.DeleteFunc(r.encodings, func(registered string) bool {
+		return registered == encoding
+	})
+	r.encodings = slices.Insert(r.encodings, 0, encoding)
+	r.encoders[encoding] = encoder
+}
+
+// Encodings returns the registered content codings from the most to the least preferred one.
+func (r *EncoderRegistry) Encodings() []string {
+	return slices.Clone(r.encodings)
+}
+
+func newGzipEncoder(w io.Writer, level int) (Encoder, error) { //nolint:ireturn
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	encoder, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return encoder, nil
+}
+
+// newDeflateEncoder creates a zlib encoder, the deflate content coding is the zlib format of RFC 1950
+// rather than a raw DEFLATE stream, see RFC 9110 section 8.4.1.2.
+func newDeflateEncoder(w io.Writer, level int) (Encoder, error) { //nolint:ireturn
+	if level == 0 {
+		level = zlib.DefaultCompression
+	}
+
+	encoder, err := zlib.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return encoder, nil
+}
+
+// negotiateEncoding returns the content coding with the highest q-value in the Accept-Encoding header,
+// ties are broken by the order of the encodings. It returns "" if none is acceptable.
+func negotiateEncoding(acceptEncoding string, encodings []string) string {
+	qValues := make(map[string]float64)
+
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(item, ";")
+
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		qValue := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					qValue = parsed
+				}
+			}
+		}
+
+		qValues[coding] = qValue
+	}
+
+	bestEncoding, bestQValue := "", 0.0
+
+	for _, encoding := range encodings {
+		qValue, ok := qValues[encoding]
+		if !ok {
+			qValue = qValues["*"]
+		}
+
+		if qValue > bestQValue {
+			bestEncoding, bestQValue = encoding, qValue
+		}
+	}
+
+	return bestEncoding
+}
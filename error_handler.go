@@ -0,0 +1,29 @@
+package httprouter
+
+import "net/http"
+
+// ErrorHandlerFunc answers a request whose handler, middleware or fallback handler returned an error.
+type ErrorHandlerFunc func(responseWriter http.ResponseWriter, request *http.Request, err error)
+
+// DefaultErrorHandler answers with the status code of the error, see ErrorStatusCode, and its status text
+// followed by the request ID if the RequestID middleware set one.
+func DefaultErrorHandler(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	statusCode := ErrorStatusCode(err)
+
+	message := http.StatusText(statusCode)
+	if requestID := RequestIDFromContext(request.Context()); requestID != "" {
+		message += " (request ID: " + requestID + ")"
+	}
+
+	http.Error(responseWriter, message, statusCode)
+}
+
+func (r *router) handleError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(responseWriter, request, err)
+
+		return
+	}
+
+	DefaultErrorHandler(responseWriter, request, err)
+}
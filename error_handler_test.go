@@ -0,0 +1,64 @@
+package httprouter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errForbidden := errors.New("forbidden")
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"PlainError", errors.New("handler error"), http.StatusInternalServerError},
+		{"HTTPError", httprouter.NewHTTPError(http.StatusForbidden, errForbidden), http.StatusForbidden},
+		{"WrappedHTTPError", fmt.Errorf("wrapped: %w", httprouter.NewHTTPError(http.StatusTooManyRequests, nil)), http.StatusTooManyRequests},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			httprouter.DefaultErrorHandler(recorder, httptest.NewRequest(http.MethodGet, "/", nil), testCase.err)
+
+			assert.Equal(t, testCase.expectedStatus, recorder.Code, "Status code mismatch")
+			assert.Equal(t, http.StatusText(testCase.expectedStatus)+"\n", recorder.Body.String())
+		})
+	}
+}
+
+func TestRouter_ErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errHandler := errors.New("handler error")
+
+	var handledErr error
+
+	router := httprouter.New()
+	router.ErrorHandler = func(responseWriter http.ResponseWriter, _ *http.Request, err error) {
+		handledErr = err
+		responseWriter.WriteHeader(http.StatusBadGateway)
+	}
+
+	router.Get("/test", &mockHandler{errToReturn: errHandler}, "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusBadGateway, recorder.Code, "Status code mismatch")
+	assert.ErrorIs(t, handledErr, errHandler)
+}
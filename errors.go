@@ -1,7 +1,59 @@
 package httprouter
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+)
 
 var ErrMethodNotAllowed = errors.New("httprouter: method not allowed")
 var ErrRouteNotFound = errors.New("httprouter: route not found")
 var ErrPathMismatch = errors.New("httprouter: Path mismatch")
+var ErrInvalidPathEscape = errors.New("httprouter: invalid path escape")
+var ErrInvalidTraceparent = errors.New("httprouter: invalid traceparent")
+var ErrRateLimited = errors.New("httprouter: rate limit exceeded")
+var ErrMissingCredentials = errors.New("httprouter: missing credentials")
+var ErrInvalidCredentials = errors.New("httprouter: invalid credentials")
+var ErrInvalidToken = errors.New("httprouter: invalid token")
+var ErrInvalidJWKS = errors.New("httprouter: invalid JWKS")
+var ErrForbidden = errors.New("httprouter: missing required scopes")
+var ErrInvalidCSRFToken = errors.New("httprouter: invalid CSRF token")
+var ErrCrossOriginRequest = errors.New("httprouter: cross-origin request")
+var ErrPreconditionFailed = errors.New("httprouter: precondition failed")
+var ErrPreconditionRequired = errors.New("httprouter: precondition required")
+var ErrIPNotAllowed = errors.New("httprouter: client IP not allowed")
+
+// HTTPError is an error that carries the status code it should be answered with.
+type HTTPError struct {
+	Code int
+	Err  error
+}
+
+func NewHTTPError(code int, err error) *HTTPError {
+	return &HTTPError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorStatusCode returns the status code of the first HTTPError in the error chain,
+// or 500 Internal Server Error if there is none.
+func ErrorStatusCode(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
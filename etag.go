@@ -0,0 +1,293 @@
+package httprouter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ETagConfig struct {
+	// Weak makes the computed ETags weak, e.g. for responses whose encoding may change while their content does not.
+	Weak bool
+	// RequirePreconditions answers PUT, PATCH and DELETE requests without If-Match or If-Unmodified-Since header
+	// with 428 Precondition Required.
+	RequirePreconditions bool
+	// Current returns the validators of the resource a PUT, PATCH or DELETE request targets, preferably read from
+	// the storage the handler writes to, they are empty if the resource does not exist. When it is nil, a GET
+	// request to the same URL is dispatched by the router, without the Pre middlewares, and the validators of its
+	// uncompressed response are used. The GET request runs the Use and With middlewares of its route, so it is
+	// authenticated, takes a rate limit token and is logged and measured by route middlewares like any request;
+	// set Current to avoid the extra request.
+	Current func(request *http.Request) (etag string, lastModified time.Time, err error)
+}
+
+// ETag buffers the 200 OK responses to GET and HEAD requests and sets their ETag header from a hash
+// of the body, unless the handler set it. It answers If-None-Match and If-Modified-Since with 304 Not Modified,
+// comparing them with the ETag and Last-Modified headers of the response. PUT, PATCH and DELETE requests run
+// only if their If-Match, If-None-Match and If-Unmodified-Since preconditions hold for the current resource,
+// other requests are answered with 412 Precondition Failed by the router error handler. Add Compress before ETag,
+// so the computed ETag is weakened when the response is compressed.
+//
+// The preconditions are checked before the handler runs, so two concurrent writes with the same If-Match header
+// may both pass. The handler should write only if the resource still has the ETag returned by CurrentETag,
+// e.g. with a compare-and-swap in the storage, and return ErrPreconditionFailed otherwise.
+func ETag(config ETagConfig) MiddlewareFunc {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			switch request.Method {
+			case http.MethodGet, http.MethodHead:
+				return serveConditional(config, next, responseWriter, request)
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				etag, err := checkPreconditions(config, request)
+				if err != nil {
+					return err
+				}
+
+				request = request.WithContext(context.WithValue(request.Context(), etagKey, etag))
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+func serveConditional(config ETagConfig, next Handler, responseWriter http.ResponseWriter, request *http.Request) error {
+	writer := newBufferedWriter()
+
+	if err := next.Handle(writer, request); err != nil {
+		writer.writeTo(responseWriter)
+
+		return err //nolint:wrapcheck
+	}
+
+	if writer.status != http.StatusOK {
+		writer.writeTo(responseWriter)
+
+		return nil
+	}
+
+	etag := writer.etag(config.Weak)
+	lastModified, _ := http.ParseTime(writer.header.Get("Last-Modified"))
+
+	switch evaluatePreconditions(request, etag, lastModified, true) {
+	case http.StatusNotModified:
+		for _, name := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+			writer.header.Del(name)
+		}
+
+		writer.status = http.StatusNotModified
+		writer.body.Reset()
+	case http.StatusPreconditionFailed:
+		writer.writeHeaders(responseWriter)
+
+		return NewHTTPError(http.StatusPreconditionFailed, ErrPreconditionFailed)
+	}
+
+	writer.writeTo(responseWriter)
+
+	return nil
+}
+
+// CurrentETag returns the ETag of the resource the ETag middleware checked the preconditions of a PUT, PATCH or DELETE
+// request against, it is empty if the resource did not exist or the request had no preconditions.
+func CurrentETag(ctx context.Context) string {
+	etag, _ := ctx.Value(etagKey).(string)
+
+	return etag
+}
+
+// checkPreconditions returns the ETag of the current resource if the preconditions of the request hold.
+func checkPreconditions(config ETagConfig, request *http.Request) (string, error) {
+	header := request.Header
+	if header.Get("If-Match") == "" && header.Get("If-None-Match") == "" && header.Get("If-Unmodified-Since") == "" {
+		if config.RequirePreconditions {
+			return "", NewHTTPError(http.StatusPreconditionRequired, ErrPreconditionRequired)
+		}
+
+		return "", nil
+	}
+
+	current := config.Current
+	if current == nil {
+		current = func(request *http.Request) (string, time.Time, error) {
+			return currentValidators(config, request)
+		}
+	}
+
+	etag, lastModified, err := current(request)
+	if err != nil {
+		return "", err
+	}
+
+	exists := etag != "" || !lastModified.IsZero()
+
+	if evaluatePreconditions(request, etag, lastModified, exists) != 0 {
+		return "", NewHTTPError(http.StatusPreconditionFailed, ErrPreconditionFailed)
+	}
+
+	return etag, nil
+}
+
+// currentValidators dispatches a GET request to the URL of the request and returns the validators of its response.
+// The GET request has no Accept-Encoding header, so Compress does not weaken the ETag strong If-Match compares.
+func currentValidators(config ETagConfig, request *http.Request) (string, time.Time, error) {
+	router, ok := request.Context().Value(routerKey).(*router)
+	if !ok {
+		return "", time.Time{}, nil
+	}
+
+	probe := request.Clone(context.WithValue(request.Context(), routeStateKey, &routeState{}))
+	probe.Method = http.MethodGet
+	probe.Body = http.NoBody
+	probe.ContentLength = 0
+
+	for _, name := range []string{
+		"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since", "If-Range", "Accept-Encoding",
+	} {
+		probe.Header.Del(name)
+	}
+
+	writer := newBufferedWriter()
+
+	if err := router.dispatch(writer, probe); err != nil || writer.status != http.StatusOK {
+		return "", time.Time{}, nil //nolint:nilerr
+	}
+
+	lastModified, _ := http.ParseTime(writer.header.Get("Last-Modified"))
+
+	return writer.etag(config.Weak), lastModified, nil
+}
+
+// evaluatePreconditions evaluates the conditional headers of the request in the order of RFC 9110 section 13.2.2,
+// it returns 304 or 412 if a precondition fails, 0 otherwise.
+func evaluatePreconditions(request *http.Request, etag string, lastModified time.Time, exists bool) int {
+	header := request.Header
+	lastModified = lastModified.Truncate(time.Second)
+
+	if ifMatch := header.Get("If-Match"); ifMatch != "" {
+		if !matchETag(ifMatch, etag, exists, true) {
+			return http.StatusPreconditionFailed
+		}
+	} else if since, err := http.ParseTime(header.Get("If-Unmodified-Since")); err == nil && !lastModified.IsZero() {
+		if lastModified.After(since) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	safe := request.Method == http.MethodGet || request.Method == http.MethodHead
+
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		if matchETag(ifNoneMatch, etag, exists, false) {
+			if safe {
+				return http.StatusNotModified
+			}
+
+			return http.StatusPreconditionFailed
+		}
+	} else if since, err := http.ParseTime(header.Get("If-Modified-Since")); err == nil && safe && !lastModified.IsZero() {
+		if !lastModified.After(since) {
+			return http.StatusNotModified
+		}
+	}
+
+	return 0
+}
+
+// matchETag tells whether the list of entity tags of a conditional header matches the etag,
+// with the strong comparison of If-Match or the weak comparison of If-None-Match.
+func matchETag(list string, etag string, exists bool, strong bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return exists
+	}
+
+	if etag == "" || (strong && strings.HasPrefix(etag, "W/")) {
+		return false
+	}
+
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if strong && strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedWriter buffers a response, so its headers can be changed after the handler returns.
+type bufferedWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func newBufferedWriter() *bufferedWriter {
+	return &bufferedWriter{header: make(http.Header), status: http.StatusOK}
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+
+	return w.body.Write(data) //nolint:wrapcheck
+}
+
+func (w *bufferedWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.status = statusCode
+	w.wroteHeader = statusCode >= http.StatusOK
+}
+
+// etag returns the ETag header of the response, it is set from a hash of the body if the handler did not set it.
+func (w *bufferedWriter) etag(weak bool) string {
+	if etag := w.header.Get("ETag"); etag != "" {
+		return etag
+	}
+
+	sum := sha256.Sum256(w.body.Bytes())
+
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+
+	w.header.Set("ETag", etag)
+
+	return etag
+}
+
+func (w *bufferedWriter) writeHeaders(responseWriter http.ResponseWriter) {
+	header := responseWriter.Header()
+	for name, values := range w.header {
+		header[name] = values
+	}
+}
+
+// writeTo copies the buffered response, headers are copied even if nothing was written,
+// so they are part of the error response to an error the handler returned.
+func (w *bufferedWriter) writeTo(responseWriter http.ResponseWriter) {
+	w.writeHeaders(responseWriter)
+
+	if !w.wroteHeader && w.status == http.StatusOK {
+		return
+	}
+
+	responseWriter.WriteHeader(w.status)
+	_, _ = responseWriter.Write(w.body.Bytes())
+}
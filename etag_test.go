@@ -0,0 +1,428 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedDocument is a resource whose ETag changes with every update.
+type versionedDocument struct {
+	mu      sync.Mutex
+	body    string
+	updated time.Time
+}
+
+func newETagRouter(config httprouter.ETagConfig, document *versionedDocument) httprouter.Router {
+	router := httprouter.New()
+	router.Use(httprouter.ETag(config))
+
+	router.Get("/document", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		document.mu.Lock()
+		defer document.mu.Unlock()
+
+		responseWriter.Header().Set("Content-Type", "text/plain")
+		responseWriter.Header().Set("Last-Modified", document.updated.UTC().Format(http.TimeFormat))
+		_, _ = responseWriter.Write([]byte(document.body))
+
+		return nil
+	}), "document")
+	router.Put("/document", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		document.mu.Lock()
+		defer document.mu.Unlock()
+
+		document.body += "!"
+		document.updated = document.updated.Add(time.Hour)
+
+		responseWriter.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}), "update-document")
+	router.Get("/versioned", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		responseWriter.Header().Set("ETag", `"v1"`)
+		_, _ = responseWriter.Write([]byte("versioned"))
+
+		return nil
+	}), "versioned")
+	router.Get("/missing", httprouter.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		return httprouter.NewHTTPError(http.StatusNotFound, nil)
+	}), "missing")
+	router.Delete("/missing", &mockHandler{}, "delete-missing")
+
+	return router
+}
+
+func serveETagRequest(router http.Handler, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	return responseRecorder
+}
+
+func TestETag_ConditionalGet(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	router := newETagRouter(httprouter.ETagConfig{}, &versionedDocument{body: "hello", updated: updated})
+
+	first := serveETagRequest(router, http.MethodGet, "/document", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "hello", first.Body.String())
+
+	testCases := []struct {
+		name           string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+		expectedETag   string
+		expectedBody   string
+	}{
+		{
+			name:           "matching If-None-Match",
+			path:           "/document",
+			headers:        map[string]string{"If-None-Match": `"other", ` + etag},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   etag,
+		},
+		{
+			name:           "weak If-None-Match matches the strong ETag",
+			path:           "/document",
+			headers:        map[string]string{"If-None-Match": "W/" + etag},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   etag,
+		},
+		{
+			name:           "other If-None-Match",
+			path:           "/document",
+			headers:        map[string]string{"If-None-Match": `"other"`},
+			expectedStatus: http.StatusOK,
+			expectedETag:   etag,
+			expectedBody:   "hello",
+		},
+		{
+			name:           "If-Modified-Since at the last modification",
+			path:           "/document",
+			headers:        map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   etag,
+		},
+		{
+			name:           "If-Modified-Since before the last modification",
+			path:           "/document",
+			headers:        map[string]string{"If-Modified-Since": updated.Add(-time.Second).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			expectedETag:   etag,
+			expectedBody:   "hello",
+		},
+		{
+			name: "If-None-Match takes precedence over If-Modified-Since",
+			path: "/document",
+			headers: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": updated.Format(http.TimeFormat),
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   etag,
+			expectedBody:   "hello",
+		},
+		{
+			name:           "ETag set by the handler",
+			path:           "/versioned",
+			headers:        map[string]string{"If-None-Match": `"v1"`},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"v1"`,
+		},
+		{
+			name:           "failed If-Match",
+			path:           "/versioned",
+			headers:        map[string]string{"If-Match": `"v0"`},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedETag:   `"v1"`,
+			expectedBody:   "Precondition Failed\n",
+		},
+		{
+			name:           "error response",
+			path:           "/missing",
+			headers:        map[string]string{"If-None-Match": "*"},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Not Found\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			responseRecorder := serveETagRequest(router, http.MethodGet, testCase.path, testCase.headers)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedETag, responseRecorder.Header().Get("ETag"))
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+
+			if testCase.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, responseRecorder.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestETag_Preconditions(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		config         httprouter.ETagConfig
+		path           string
+		headers        func(etag string) map[string]string
+		expectedStatus int
+	}{
+		{
+			name: "If-Match with the current ETag",
+			path: "/document",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-Match": etag}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "If-Match with a stale ETag",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Match": `"stale"`}
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "If-Match with a weak ETag",
+			config: httprouter.ETagConfig{Weak: true},
+			path:   "/document",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-Match": etag}
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "If-Match any of an existing resource",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Match": "*"}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "If-Match any of a missing resource",
+			path: "/missing",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Match": "*"}
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "If-None-Match any of an existing resource",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-None-Match": "*"}
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "If-Unmodified-Since after the last modification",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Unmodified-Since": updated.Format(http.TimeFormat)}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "If-Unmodified-Since before the last modification",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Unmodified-Since": updated.Add(-time.Second).Format(http.TimeFormat)}
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "no precondition",
+			path: "/document",
+			headers: func(string) map[string]string {
+				return nil
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "required precondition",
+			config: httprouter.ETagConfig{RequirePreconditions: true},
+			path:   "/document",
+			headers: func(string) map[string]string {
+				return nil
+			},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name: "custom current validators",
+			config: httprouter.ETagConfig{Current: func(*http.Request) (string, time.Time, error) {
+				return `"v7"`, time.Time{}, nil
+			}},
+			path: "/document",
+			headers: func(string) map[string]string {
+				return map[string]string{"If-Match": `"v7"`}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := newETagRouter(testCase.config, &versionedDocument{body: "hello", updated: updated})
+
+			etag := serveETagRequest(router, http.MethodGet, "/document", nil).Header().Get("ETag")
+
+			method := http.MethodPut
+			if testCase.path == "/missing" {
+				method = http.MethodDelete
+			}
+
+			responseRecorder := serveETagRequest(router, method, testCase.path, testCase.headers(etag))
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+		})
+	}
+}
+
+func TestETag_OptimisticConcurrency(t *testing.T) {
+	t.Parallel()
+
+	router := newETagRouter(httprouter.ETagConfig{}, &versionedDocument{body: "hello"})
+
+	etag := serveETagRequest(router, http.MethodGet, "/document", nil).Header().Get("ETag")
+
+	assert.Equal(t, http.StatusNoContent, serveETagRequest(router, http.MethodPut, "/document", map[string]string{"If-Match": etag}).Code)
+	assert.Equal(t, http.StatusPreconditionFailed, serveETagRequest(router, http.MethodPut, "/document", map[string]string{"If-Match": etag}).Code,
+		"the second update with the same ETag loses the race")
+}
+
+func TestETag_Compress(t *testing.T) {
+	t.Parallel()
+
+	document := &versionedDocument{body: "hello"}
+
+	router := httprouter.New()
+	router.Use(httprouter.Compress(httprouter.CompressConfig{MinSize: 1}), httprouter.ETag(httprouter.ETagConfig{}))
+	router.Get("/document", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		document.mu.Lock()
+		defer document.mu.Unlock()
+
+		responseWriter.Header().Set("Content-Type", "text/plain")
+		_, _ = responseWriter.Write([]byte(document.body))
+
+		return nil
+	}), "document")
+	router.Put("/document", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		document.mu.Lock()
+		defer document.mu.Unlock()
+
+		document.body += "!"
+
+		responseWriter.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}), "update-document")
+
+	compressed := serveETagRequest(router, http.MethodGet, "/document", map[string]string{"Accept-Encoding": "gzip"})
+	require.Equal(t, "gzip", compressed.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(compressed.Header().Get("ETag"), "W/"), "the compressed response has a weak ETag")
+
+	etag := serveETagRequest(router, http.MethodGet, "/document", nil).Header().Get("ETag")
+	require.False(t, strings.HasPrefix(etag, "W/"))
+
+	headers := map[string]string{"If-Match": etag, "Accept-Encoding": "gzip"}
+	assert.Equal(t, http.StatusNoContent, serveETagRequest(router, http.MethodPut, "/document", headers).Code)
+	assert.Equal(t, http.StatusPreconditionFailed, serveETagRequest(router, http.MethodPut, "/document", headers).Code)
+}
+
+func TestETag_ConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		version int
+	)
+
+	currentETag := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return `"v` + strconv.Itoa(version) + `"`
+	}
+
+	// both writes pass the precondition check before either of them updates the resource
+	var checked sync.WaitGroup
+
+	checked.Add(2)
+
+	router := httprouter.New()
+	router.Use(httprouter.ETag(httprouter.ETagConfig{
+		Current: func(*http.Request) (string, time.Time, error) {
+			return currentETag(), time.Time{}, nil
+		},
+	}))
+	router.Put("/document", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+		checked.Done()
+		checked.Wait()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if `"v`+strconv.Itoa(version)+`"` != httprouter.CurrentETag(request.Context()) {
+			return httprouter.NewHTTPError(http.StatusPreconditionFailed, httprouter.ErrPreconditionFailed)
+		}
+
+		version++
+
+		responseWriter.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}), "update-document")
+
+	codes := make([]int, 2)
+
+	var wg sync.WaitGroup
+
+	for idx := range codes {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			codes[idx] = serveETagRequest(router, http.MethodPut, "/document", map[string]string{"If-Match": `"v0"`}).Code
+		}()
+	}
+
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusNoContent, http.StatusPreconditionFailed}, codes)
+	assert.Equal(t, `"v1"`, currentETag())
+}
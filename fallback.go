@@ -0,0 +1,88 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// fallback holds the NotFound and MethodNotAllowed handlers registered for a prefix.
+type fallback struct {
+	prefix           string
+	notFound         Handler
+	methodNotAllowed Handler
+}
+
+// NotFound sets the handler for requests under the scope prefix that match no route, the handler is wrapped
+// with the scope middleware. The fallback with the longest prefix wins, router NotFoundHandler is used
+// when there is none.
+func (s *scope) NotFound(handler Handler) {
+	if s.middleware != nil {
+		handler = s.middleware(handler)
+	}
+
+	s.router.fallback(s.prefix).notFound = handler
+}
+
+// MethodNotAllowed sets the handler for requests under the scope prefix that match a route path but not its methods,
+// the handler is wrapped with the scope middleware. The fallback with the longest prefix wins.
+func (s *scope) MethodNotAllowed(handler Handler) {
+	if s.middleware != nil {
+		handler = s.middleware(handler)
+	}
+
+	s.router.fallback(s.prefix).methodNotAllowed = handler
+}
+
+func (r *router) fallback(prefix string) *fallback {
+	prefix = "/" + prefix
+
+	for _, fallback := range r.fallbacks {
+		if fallback.prefix == prefix {
+			return fallback
+		}
+	}
+
+	fallback := &fallback{prefix: prefix}
+	r.fallbacks = append(r.fallbacks, fallback)
+
+	return fallback
+}
+
+// fallbackHandler returns the handler of the longest fallback prefix that matches the request path,
+// the request returned with it carries params of the prefix.
+func (r *router) fallbackHandler(request *http.Request, handlerOf func(fallback *fallback) Handler) (Handler, *http.Request) {
+	var handler Handler
+	var params RouteParams
+
+	prefixSegments := -1
+
+	for _, fallback := range r.fallbacks {
+		fallbackHandler := handlerOf(fallback)
+		if fallbackHandler == nil {
+			continue
+		}
+
+		fallbackParams, ok := matchPathPrefix(fallback.prefix, request.URL.Path)
+		if !ok || len(pathSegments(fallback.prefix)) <= prefixSegments {
+			continue
+		}
+
+		handler = fallbackHandler
+		params = fallbackParams
+		prefixSegments = len(pathSegments(fallback.prefix))
+	}
+
+	if len(params) != 0 {
+		request = request.WithContext(context.WithValue(request.Context(), routeParamsKey, mergeRouteParams(request.Context(), params)))
+	}
+
+	return handler, request
+}
+
+func notFoundHandlerOf(fallback *fallback) Handler {
+	return fallback.notFound
+}
+
+func methodNotAllowedHandlerOf(fallback *fallback) Handler {
+	return fallback.methodNotAllowed
+}
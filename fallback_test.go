@@ -0,0 +1,105 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func statusHandler(status int, body string) httprouter.Handler {
+	return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+		responseWriter.WriteHeader(status)
+		_, _ = responseWriter.Write([]byte(body))
+
+		return nil
+	})
+}
+
+//nolint:funlen
+func TestScope_NotFoundAndMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.NotFoundHandler = statusHandler(http.StatusNotFound, "root not found")
+
+	router.Route("/api", func(api httprouter.Router) {
+		api.Use(headerMiddleware("api"))
+		api.NotFound(statusHandler(http.StatusNotFound, `{"error":"not found"}`))
+		api.MethodNotAllowed(statusHandler(http.StatusMethodNotAllowed, `{"error":"method not allowed"}`))
+
+		api.Get("/users", &mockHandler{}, "")
+
+		api.Route("/v2", func(v2 httprouter.Router) {
+			v2.NotFound(statusHandler(http.StatusNotFound, `{"error":"v2 not found"}`))
+		})
+	})
+
+	router.Route("/admin", func(admin httprouter.Router) {
+		admin.NotFound(httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			http.Redirect(responseWriter, request, "/login", http.StatusFound)
+
+			return nil
+		}))
+	})
+
+	testCases := []struct {
+		name               string
+		method             string
+		path               string
+		expectedStatus     int
+		expectedBody       string
+		expectedMiddleware []string
+	}{
+		{"RootNotFound", http.MethodGet, "/unknown", http.StatusNotFound, "root not found", nil},
+		{"PrefixNotFound", http.MethodGet, "/api/unknown", http.StatusNotFound, `{"error":"not found"}`, []string{"api"}},
+		{"PrefixItselfNotFound", http.MethodGet, "/api", http.StatusNotFound, `{"error":"not found"}`, []string{"api"}},
+		{"LongestPrefixWins", http.MethodGet, "/api/v2/unknown", http.StatusNotFound, `{"error":"v2 not found"}`, []string{"api"}},
+		{"PartialSegmentIsNotPrefix", http.MethodGet, "/apis", http.StatusNotFound, "root not found", nil},
+		{"PrefixMethodNotAllowed", http.MethodPost, "/api/users", http.StatusMethodNotAllowed, `{"error":"method not allowed"}`, []string{"api"}},
+		{"Redirect", http.MethodGet, "/admin/users", http.StatusFound, "", nil},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(testCase.method, testCase.path, nil))
+
+			assert.Equal(t, testCase.expectedStatus, recorder.Code, "Status code mismatch")
+			assert.Equal(t, testCase.expectedMiddleware, recorder.Header().Values("X-Middleware"))
+
+			if testCase.expectedBody != "" {
+				assert.Equal(t, testCase.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestScope_NotFoundWithPrefixParams(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+
+	var tenantID string
+
+	router.Route("/tenants/:tid", func(tenant httprouter.Router) {
+		tenant.NotFound(httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			tenantID = httprouter.RouteParam(request.Context(), "tid")
+			responseWriter.WriteHeader(http.StatusNotFound)
+
+			return nil
+		}))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tenants/7/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "7", tenantID)
+}
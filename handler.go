@@ -15,3 +15,24 @@ func (f HandlerFunc) Handle(responseWriter http.ResponseWriter, request *http.Re
 }
 
 type MiddlewareFunc func(next Handler) Handler
+
+// chainMiddleware appends middlewares to the chain, so chainMiddleware(nil, middleware1, middleware2)(next)
+// is middleware1(middleware2(next)).
+func chainMiddleware(chain MiddlewareFunc, middlewares ...MiddlewareFunc) MiddlewareFunc {
+	for _, middleware := range middlewares {
+		middleware := middleware // shadow for closure
+
+		if chain != nil {
+			outerMiddleware := chain
+			chain = func(next Handler) Handler {
+				return outerMiddleware(middleware(next))
+			}
+
+			continue
+		}
+
+		chain = middleware
+	}
+
+	return chain
+}
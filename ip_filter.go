@@ -0,0 +1,137 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync/atomic"
+)
+
+type IPFilterRules struct {
+	// Allow lists the CIDRs or single IPs allowed, all addresses not denied are allowed when it is empty.
+	Allow []string
+	// Deny lists the CIDRs or single IPs denied, it takes precedence over Allow.
+	Deny []string
+}
+
+// IPFilter allows or denies client IPs, IPv4 or IPv6, by CIDR. Its rules can be replaced while it is used.
+type IPFilter struct {
+	rules atomic.Pointer[ipFilterRules]
+}
+
+type ipFilterRules struct {
+	rules IPFilterRules
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// IPFilters is the metadata listing the filters a route is restricted by, read it with Meta to audit the routes.
+type IPFilters []*IPFilter
+
+func NewIPFilter(rules IPFilterRules) (*IPFilter, error) {
+	filter := &IPFilter{}
+
+	if err := filter.Update(rules); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Update replaces the rules, e.g. when the file they are loaded from changes. The rules in use are kept
+// if the new ones are invalid.
+func (f *IPFilter) Update(rules IPFilterRules) error {
+	allow, err := parsePrefixes(rules.Allow)
+	if err != nil {
+		return err
+	}
+
+	deny, err := parsePrefixes(rules.Deny)
+	if err != nil {
+		return err
+	}
+
+	f.rules.Store(&ipFilterRules{
+		rules: IPFilterRules{Allow: slices.Clone(rules.Allow), Deny: slices.Clone(rules.Deny)},
+		allow: allow,
+		deny:  deny,
+	})
+
+	return nil
+}
+
+// Rules returns the rules in use.
+func (f *IPFilter) Rules() IPFilterRules {
+	rules := f.rules.Load().rules
+
+	return IPFilterRules{Allow: slices.Clone(rules.Allow), Deny: slices.Clone(rules.Deny)}
+}
+
+// Allowed tells whether the IP is allowed, invalid IPs are not.
+func (f *IPFilter) Allowed(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	rules := f.rules.Load()
+
+	contains := func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	}
+
+	if slices.ContainsFunc(rules.deny, contains) {
+		return false
+	}
+
+	return len(rules.allow) == 0 || slices.ContainsFunc(rules.allow, contains)
+}
+
+// RestrictIPs returns a router value whose routes are served only to clients allowed by the filter, other clients
+// are answered with 403 Forbidden by the router error handler. The client IP is the one resolved by ProxyHeaders,
+// or the IP of the remote address. Groups created from it inherit the restriction, and the filters of nested
+// groups are added to it.
+func RestrictIPs(r Router, filter *IPFilter) Router {
+	if filter == nil {
+		panic("httprouter: IP filter is nil")
+	}
+
+	router, ok := r.(metaRouter)
+	if !ok {
+		panic(fmt.Sprintf("httprouter: %T does not support metadata", r))
+	}
+
+	inherited, _ := router.metaValue(metaKey[IPFilters]{})
+	filters, _ := inherited.(IPFilters)
+
+	return router.withMeta(metaKey[IPFilters]{}, append(slices.Clip(filters), filter)).With(restrictIPs(filter))
+}
+
+func restrictIPs(filter *IPFilter) MiddlewareFunc {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if !filter.Allowed(ClientIP(request)) {
+				return NewHTTPError(http.StatusForbidden, ErrIPNotAllowed)
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+
+	for _, value := range values {
+		prefix, err := parsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("httprouter: invalid IP filter rule %q: %w", value, err)
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
@@ -0,0 +1,174 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPFilter_Allowed(t *testing.T) {
+	t.Parallel()
+
+	filter, err := httprouter.NewIPFilter(httprouter.IPFilterRules{
+		Allow: []string{"10.0.0.0/8", "2001:db8::/32"},
+		Deny:  []string{"10.0.0.13", "2001:db8:bad::/48"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "10.1.2.3", expected: true},
+		{ip: "::ffff:10.1.2.3", expected: true},
+		{ip: "10.0.0.13", expected: false},
+		{ip: "192.168.1.1", expected: false},
+		{ip: "2001:db8::1", expected: true},
+		{ip: "2001:db8:bad::1", expected: false},
+		{ip: "invalid", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.ip, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, filter.Allowed(testCase.ip))
+		})
+	}
+}
+
+func TestIPFilter_Update(t *testing.T) {
+	t.Parallel()
+
+	filter, err := httprouter.NewIPFilter(httprouter.IPFilterRules{Allow: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+
+	_, err = httprouter.NewIPFilter(httprouter.IPFilterRules{Deny: []string{"10.0.0.0/33"}})
+	require.Error(t, err)
+
+	require.Error(t, filter.Update(httprouter.IPFilterRules{Allow: []string{"not an IP"}}))
+	assert.True(t, filter.Allowed("10.0.0.1"), "invalid rules keep the rules in use")
+
+	require.NoError(t, filter.Update(httprouter.IPFilterRules{Allow: []string{"192.168.0.0/16"}}))
+	assert.False(t, filter.Allowed("10.0.0.1"))
+	assert.True(t, filter.Allowed("192.168.0.1"))
+	assert.Equal(t, httprouter.IPFilterRules{Allow: []string{"192.168.0.0/16"}}, filter.Rules())
+}
+
+func TestRestrictIPs(t *testing.T) {
+	t.Parallel()
+
+	internal, err := httprouter.NewIPFilter(httprouter.IPFilterRules{Allow: []string{"10.0.0.0/8", "fd00::/8"}})
+	require.NoError(t, err)
+
+	admins, err := httprouter.NewIPFilter(httprouter.IPFilterRules{Allow: []string{"10.1.0.0/16"}})
+	require.NoError(t, err)
+
+	router := httprouter.New()
+	router.Pre(httprouter.ProxyHeaders(httprouter.ProxyHeadersConfig{TrustedProxies: []string{"192.0.2.1"}}))
+
+	router.Get("/", &mockHandler{}, "home")
+
+	httprouter.RestrictIPs(router, internal).Route("/internal", func(group httprouter.Router) {
+		group.Get("/status", &mockHandler{}, "status")
+		httprouter.RestrictIPs(group, admins).Get("/admin", &mockHandler{}, "admin")
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		remoteAddr     string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{
+			name:           "unrestricted route",
+			path:           "/",
+			remoteAddr:     "203.0.113.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "allowed IPv4",
+			path:           "/internal/status",
+			remoteAddr:     "10.2.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "allowed IPv6",
+			path:           "/internal/status",
+			remoteAddr:     "[fd00::1]:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not allowed",
+			path:           "/internal/status",
+			remoteAddr:     "203.0.113.1:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "client IP resolved behind a trusted proxy",
+			path:           "/internal/status",
+			remoteAddr:     "192.0.2.1:1234",
+			forwardedFor:   "10.2.0.1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "proxy IP is not the client IP",
+			path:           "/internal/status",
+			remoteAddr:     "192.0.2.1:1234",
+			forwardedFor:   "203.0.113.1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "allowed by the nested filter",
+			path:           "/internal/admin",
+			remoteAddr:     "10.1.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "allowed by the group filter only",
+			path:           "/internal/admin",
+			remoteAddr:     "10.2.0.1:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			request.RemoteAddr = testCase.remoteAddr
+
+			if testCase.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+		})
+	}
+
+	restricted := map[string]httprouter.IPFilters{}
+
+	for _, routeInfo := range router.Routes() {
+		if filters, ok := httprouter.Meta[httprouter.IPFilters](routeInfo); ok {
+			restricted[routeInfo.Name] = filters
+		}
+	}
+
+	assert.Equal(t, map[string]httprouter.IPFilters{
+		"status": {internal},
+		"admin":  {internal, admins},
+	}, restricted)
+}
@@ -0,0 +1,172 @@
+package httprouter
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+)
+
+// JWKS is a JSON Web Key Set of the RSA and P-256 keys verifying RS256 and ES256 tokens.
+type JWKS struct {
+	keys map[string]crypto.PublicKey
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set. Keys which are not signature keys or whose type or curve is not supported
+// are ignored.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJWKS, err.Error())
+	}
+
+	jwks := &JWKS{keys: make(map[string]crypto.PublicKey, len(set.Keys))}
+
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		var (
+			publicKey crypto.PublicKey
+			err       error
+		)
+
+		switch key.Kty {
+		case "RSA":
+			publicKey, err = rsaPublicKey(key)
+		case "EC":
+			if key.Crv != "P-256" {
+				continue
+			}
+
+			publicKey, err = ecdsaPublicKey(key)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %s", ErrInvalidJWKS, key.Kid, err.Error())
+		}
+
+		jwks.keys[key.Kid] = publicKey
+	}
+
+	return jwks, nil
+}
+
+// LoadJWKSFile reads and parses the JSON Web Key Set stored in the file.
+func LoadJWKSFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("httprouter: load JWKS: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
+
+// FetchJWKS gets and parses the JSON Web Key Set served at the url, http.DefaultClient is used when client is nil.
+func FetchJWKS(ctx context.Context, client *http.Client, url string) (*JWKS, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("httprouter: fetch JWKS: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("httprouter: fetch JWKS: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %s", ErrInvalidJWKS, response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("httprouter: fetch JWKS: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
+
+// Key returns the key with the key ID. The empty key ID matches the only key of a set holding one key.
+func (s *JWKS) Key(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+
+	return key, ok
+}
+
+func rsaPublicKey(key jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if len(modulus) == 0 || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA key")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
+
+func ecdsaPublicKey(key jsonWebKey) (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %w", err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %w", err)
+	}
+
+	// ecdh checks the point is on the curve
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid EC key: %w", err)
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
@@ -0,0 +1,246 @@
+package httprouter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type JWTAuthConfig struct {
+	// Secret verifies HS256 tokens.
+	Secret []byte
+	// Keys verify RS256 and ES256 tokens, they are *rsa.PublicKey or *ecdsa.PublicKey of the P-256 curve
+	// by key ID. The empty key ID matches tokens without a kid header.
+	Keys map[string]crypto.PublicKey
+	// JWKS verifies RS256 and ES256 tokens whose key ID is not in Keys.
+	JWKS *JWKS
+	// Issuer and Audience the iss and aud claims must match when they are not empty.
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated when checking the exp and nbf claims.
+	Leeway time.Duration
+	// Now returns the current time, time.Now is used when it is nil.
+	Now func() time.Time
+	// Realm is sent in the WWW-Authenticate challenge, "Restricted" is used when it is empty.
+	Realm string
+}
+
+// JWTAuth authenticates requests with a JWT bearer token signed with HS256, RS256 or ES256.
+// Requests without a valid token are answered with 401 Unauthorized and a Bearer challenge by the router
+// error handler. The claims of the token are available with IdentityFromContext, with the sub claim
+// as subject and the scope or scp claim as scopes.
+func JWTAuth(config JWTAuthConfig) MiddlewareFunc {
+	if len(config.Secret) == 0 && len(config.Keys) == 0 && config.JWKS == nil {
+		panic("httprouter: JWT keys are nil")
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	if config.Realm == "" {
+		config.Realm = defaultRealm
+	}
+
+	challenge := "Bearer realm=" + strconv.Quote(config.Realm)
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			token, ok := bearerToken(request)
+			if !ok {
+				return unauthorized(responseWriter, challenge, ErrMissingCredentials)
+			}
+
+			claims, description := verifyJWT(config, token)
+			if description != "" {
+				return unauthorized(responseWriter,
+					challenge+`, error="invalid_token", error_description=`+strconv.Quote(description),
+					fmt.Errorf("%w: %s", ErrInvalidToken, description))
+			}
+
+			identity := Identity{Scheme: "Bearer", Scopes: jwtScopes(claims), Claims: claims}
+			identity.Subject, _ = claims["sub"].(string)
+
+			return next.Handle(responseWriter, withIdentity(request, identity)) //nolint:wrapcheck
+		})
+	}
+}
+
+func bearerToken(request *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+// verifyJWT returns the claims of the token, or the reason the token is invalid.
+func verifyJWT(config JWTAuthConfig, token string) (map[string]any, string) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, "malformed token"
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, "malformed header"
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, "malformed signature"
+	}
+
+	if !verifyJWTSignature(config, header.Alg, header.Kid, parts[0]+"."+parts[1], signature) {
+		return nil, "invalid signature"
+	}
+
+	var claims map[string]any
+	if err := decodeJWTPart(parts[1], &claims); err != nil || claims == nil {
+		return nil, "malformed claims"
+	}
+
+	return claims, checkJWTClaims(config, claims)
+}
+
+func decodeJWTPart(part string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return json.Unmarshal(data, value) //nolint:wrapcheck
+}
+
+func verifyJWTSignature(config JWTAuthConfig, alg, kid, signingInput string, signature []byte) bool {
+	hash := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "HS256":
+		if len(config.Secret) == 0 {
+			return false
+		}
+
+		mac := hmac.New(sha256.New, config.Secret)
+		mac.Write([]byte(signingInput))
+
+		return hmac.Equal(mac.Sum(nil), signature)
+	case "RS256":
+		key, ok := jwtKey(config, kid).(*rsa.PublicKey)
+
+		return ok && rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) == nil
+	case "ES256":
+		key, ok := jwtKey(config, kid).(*ecdsa.PublicKey)
+		if !ok || key.Curve != elliptic.P256() || len(signature) != 64 {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+
+		return ecdsa.Verify(key, hash[:], r, s)
+	default:
+		return false
+	}
+}
+
+func jwtKey(config JWTAuthConfig, kid string) crypto.PublicKey {
+	if key, ok := config.Keys[kid]; ok {
+		return key
+	}
+
+	if config.JWKS != nil {
+		if key, ok := config.JWKS.Key(kid); ok {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func checkJWTClaims(config JWTAuthConfig, claims map[string]any) string {
+	now := config.Now()
+
+	if exp, ok := claims["exp"]; ok {
+		expiresAt, ok := exp.(float64)
+		if !ok {
+			return "malformed exp claim"
+		}
+
+		if now.After(time.Unix(int64(expiresAt), 0).Add(config.Leeway)) {
+			return "token is expired"
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		notBefore, ok := nbf.(float64)
+		if !ok {
+			return "malformed nbf claim"
+		}
+
+		if now.Before(time.Unix(int64(notBefore), 0).Add(-config.Leeway)) {
+			return "token is not valid yet"
+		}
+	}
+
+	if config.Issuer != "" && claims["iss"] != config.Issuer {
+		return "invalid issuer"
+	}
+
+	if config.Audience != "" && !slices.Contains(jwtStrings(claims["aud"]), config.Audience) {
+		return "invalid audience"
+	}
+
+	return ""
+}
+
+func jwtScopes(claims map[string]any) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+
+	if scope, ok := claims["scp"].(string); ok {
+		return strings.Fields(scope)
+	}
+
+	return jwtStrings(claims["scp"])
+}
+
+// jwtStrings returns a claim which is a string or an array of strings.
+func jwtStrings(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := make([]string, 0, len(claim))
+
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
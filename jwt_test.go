@@ -0,0 +1,336 @@
+package httprouter_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jwtKeys struct {
+	secret []byte
+	rsa    *rsa.PrivateKey
+	ecdsa  *ecdsa.PrivateKey
+}
+
+func newJWTKeys(t *testing.T) jwtKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return jwtKeys{secret: []byte("hmac-secret"), rsa: rsaKey, ecdsa: ecdsaKey}
+}
+
+func (k jwtKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, hash[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ecdsa, hash[:])
+		require.NoError(t, err)
+
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (k jwtKeys) jwks(t *testing.T) []byte {
+	t.Helper()
+
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(k.rsa.N), "e": encode(big.NewInt(int64(k.rsa.E)))},
+		{
+			"kty": "EC", "kid": "ec-1", "crv": "P-256",
+			"x": base64.RawURLEncoding.EncodeToString(k.ecdsa.X.FillBytes(make([]byte, 32))),
+			"y": base64.RawURLEncoding.EncodeToString(k.ecdsa.Y.FillBytes(make([]byte, 32))),
+		},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "invalid"},
+	}})
+	require.NoError(t, err)
+
+	return data
+}
+
+//nolint:funlen
+func TestJWTAuth(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTKeys(t)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	claims := func(overrides map[string]any) map[string]any {
+		claims := map[string]any{
+			"sub":   "alice",
+			"iss":   "https://issuer.example.com",
+			"aud":   []string{"api", "web"},
+			"exp":   now.Add(time.Minute).Unix(),
+			"scope": "users:read users:write",
+		}
+
+		for name, value := range overrides {
+			if value == nil {
+				delete(claims, name)
+
+				continue
+			}
+
+			claims[name] = value
+		}
+
+		return claims
+	}
+
+	testCases := []struct {
+		name              string
+		authorization     string
+		expectedStatus    int
+		expectedBody      string
+		expectedChallenge string
+	}{
+		{
+			name:           "HS256 token",
+			authorization:  "Bearer " + keys.sign(t, "HS256", "", claims(nil)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "alice [users:read users:write]",
+		},
+		{
+			name:           "RS256 token with a key of the JWKS",
+			authorization:  "Bearer " + keys.sign(t, "RS256", "rsa-1", claims(nil)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "alice [users:read users:write]",
+		},
+		{
+			name:           "ES256 token with a key of the JWKS",
+			authorization:  "bearer " + keys.sign(t, "ES256", "ec-1", claims(map[string]any{"scope": nil, "scp": []string{"admin"}})),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "alice [admin]",
+		},
+		{
+			name:              "missing token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api"`,
+		},
+		{
+			name:              "tampered token",
+			authorization:     "Bearer " + keys.sign(t, "HS256", "", claims(nil)) + "x",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="invalid signature"`,
+		},
+		{
+			name:              "RS256 token signed by an unknown key ID",
+			authorization:     "Bearer " + keys.sign(t, "RS256", "rsa-2", claims(nil)),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="invalid signature"`,
+		},
+		{
+			name:              "unsigned token",
+			authorization:     "Bearer " + strings.TrimSuffix(keys.sign(t, "none", "", claims(nil)), "."),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="malformed token"`,
+		},
+		{
+			name:              "expired token",
+			authorization:     "Bearer " + keys.sign(t, "HS256", "", claims(map[string]any{"exp": now.Add(-time.Minute).Unix()})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="token is expired"`,
+		},
+		{
+			name:           "expired token within the leeway",
+			authorization:  "Bearer " + keys.sign(t, "HS256", "", claims(map[string]any{"exp": now.Add(-time.Second).Unix()})),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "alice [users:read users:write]",
+		},
+		{
+			name:              "token not valid yet",
+			authorization:     "Bearer " + keys.sign(t, "HS256", "", claims(map[string]any{"nbf": now.Add(time.Minute).Unix()})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="token is not valid yet"`,
+		},
+		{
+			name:              "wrong issuer",
+			authorization:     "Bearer " + keys.sign(t, "HS256", "", claims(map[string]any{"iss": "https://other.example.com"})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="invalid issuer"`,
+		},
+		{
+			name:              "wrong audience",
+			authorization:     "Bearer " + keys.sign(t, "HS256", "", claims(map[string]any{"aud": "web"})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      "Unauthorized\n",
+			expectedChallenge: `Bearer realm="api", error="invalid_token", error_description="invalid audience"`,
+		},
+	}
+
+	jwks, err := httprouter.ParseJWKS(keys.jwks(t))
+	require.NoError(t, err)
+
+	router := httprouter.New()
+	router.Use(httprouter.JWTAuth(httprouter.JWTAuthConfig{
+		Secret:   keys.secret,
+		JWKS:     jwks,
+		Issuer:   "https://issuer.example.com",
+		Audience: "api",
+		Leeway:   5 * time.Second,
+		Now:      func() time.Time { return now },
+		Realm:    "api",
+	}))
+	router.Get("/users", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+		identity, _ := httprouter.IdentityFromContext(request.Context())
+
+		_, _ = responseWriter.Write([]byte(identity.Subject + " [" + strings.Join(identity.Scopes, " ") + "]"))
+
+		return nil
+	}), "")
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if testCase.authorization != "" {
+				request.Header.Set("Authorization", testCase.authorization)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+			assert.Equal(t, testCase.expectedChallenge, responseRecorder.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTKeys(t)
+	data := keys.jwks(t)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		_, _ = responseWriter.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	fromFile, err := httprouter.LoadJWKSFile(path)
+	require.NoError(t, err)
+
+	fromHandler, err := httprouter.FetchJWKS(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+
+	for _, jwks := range []*httprouter.JWKS{fromFile, fromHandler} {
+		rsaKey, ok := jwks.Key("rsa-1")
+		require.True(t, ok)
+		assert.True(t, keys.rsa.PublicKey.Equal(rsaKey))
+
+		ecdsaKey, ok := jwks.Key("ec-1")
+		require.True(t, ok)
+		assert.True(t, keys.ecdsa.PublicKey.Equal(ecdsaKey))
+
+		_, ok = jwks.Key("enc-1")
+		assert.False(t, ok)
+	}
+
+	_, err = httprouter.ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec-1","crv":"P-256","x":"AA","y":"AA"}]}`))
+	assert.ErrorIs(t, err, httprouter.ErrInvalidJWKS)
+}
+
+func TestParseJWKS_UnsupportedKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTKeys(t)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	var set struct {
+		Keys []map[string]any `json:"keys"`
+	}
+
+	require.NoError(t, json.Unmarshal(keys.jwks(t), &set))
+
+	set.Keys = append(set.Keys,
+		map[string]any{
+			"kty": "EC",
+			"kid": "ec-384",
+			"crv": "P-384",
+			"x":   base64.RawURLEncoding.EncodeToString(p384Key.X.Bytes()),
+			"y":   base64.RawURLEncoding.EncodeToString(p384Key.Y.Bytes()),
+		},
+		map[string]any{"kty": "EC", "kid": "ec-521", "crv": "P-521", "x": "AA", "y": "AA"},
+		map[string]any{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": "AA"},
+	)
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	jwks, err := httprouter.ParseJWKS(data)
+	require.NoError(t, err)
+
+	for _, kid := range []string{"rsa-1", "ec-1"} {
+		_, ok := jwks.Key(kid)
+		assert.True(t, ok, kid)
+	}
+
+	for _, kid := range []string{"ec-384", "ec-521", "ed-1"} {
+		_, ok := jwks.Key(kid)
+		assert.False(t, ok, kid)
+	}
+}
@@ -0,0 +1,51 @@
+package httprouter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+type MaxBodySizeConfig struct {
+	// Limit is the number of bytes of the request body the next handler may read, it is required.
+	Limit int64
+}
+
+// MaxBodySize limits the request body with http.MaxBytesReader. A request whose handler returned
+// the *http.MaxBytesError of reading past the limit, even wrapped in another error, is answered with
+// 413 Content Too Large by the router error handler. The innermost MaxBodySize replaces the limits of the outer ones,
+// so a route can raise the limit set for the router with Use or for its group.
+func MaxBodySize(config MaxBodySizeConfig) MiddlewareFunc {
+	if config.Limit <= 0 {
+		panic("httprouter: max body size must be positive")
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if request.Body == nil {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			ctx := request.Context()
+
+			body, ok := ctx.Value(originalBodyKey).(io.ReadCloser)
+			if !ok {
+				body = request.Body
+				ctx = context.WithValue(ctx, originalBodyKey, body)
+			}
+
+			request = request.WithContext(ctx)
+			request.Body = http.MaxBytesReader(responseWriter, body, config.Limit)
+
+			err := next.Handle(responseWriter, request)
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) && ErrorStatusCode(err) != http.StatusRequestEntityTooLarge {
+				return NewHTTPError(http.StatusRequestEntityTooLarge, err)
+			}
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
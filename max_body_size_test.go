@@ -0,0 +1,113 @@
+package httprouter_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	readBody := httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		_, _ = responseWriter.Write(body)
+
+		return nil
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		chunked        bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "body within the router limit",
+			path:           "/users",
+			body:           strings.Repeat("a", 10),
+			expectedStatus: http.StatusOK,
+			expectedBody:   strings.Repeat("a", 10),
+		},
+		{
+			name:           "body over the router limit",
+			path:           "/users",
+			body:           strings.Repeat("a", 11),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+		{
+			name:           "chunked body over the router limit",
+			path:           "/users",
+			body:           strings.Repeat("a", 11),
+			chunked:        true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+		{
+			name:           "read error wrapped by the handler",
+			path:           "/wrapped",
+			body:           strings.Repeat("a", 11),
+			chunked:        true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+		{
+			name:           "body within the raised route limit",
+			path:           "/upload",
+			body:           strings.Repeat("a", 50),
+			expectedStatus: http.StatusOK,
+			expectedBody:   strings.Repeat("a", 50),
+		},
+		{
+			name:           "body over the raised route limit",
+			path:           "/upload",
+			body:           strings.Repeat("a", 101),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Use(httprouter.MaxBodySize(httprouter.MaxBodySizeConfig{Limit: 10}))
+			router.Post("/users", readBody, "")
+			router.Post("/wrapped", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+				if err := readBody(responseWriter, request); err != nil {
+					return httprouter.NewHTTPError(http.StatusBadRequest, errors.Join(errors.New("invalid body"), err))
+				}
+
+				return nil
+			}), "")
+			router.With(httprouter.MaxBodySize(httprouter.MaxBodySizeConfig{Limit: 100})).Post("/upload", readBody, "")
+
+			request := httptest.NewRequest(http.MethodPost, testCase.path, strings.NewReader(testCase.body))
+			if testCase.chunked {
+				request.ContentLength = -1
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package httprouter
+
+import (
+	"context"
+	"fmt"
+)
+
+// metaKey identifies metadata by its type, so values of different types never collide.
+type metaKey[T any] struct{}
+
+type metaRouter interface {
+	withMeta(key any, value any) Router
+	metaValue(key any) (any, bool)
+}
+
+// WithMeta returns a router value that attaches the metadata to every route registered through it,
+// e.g. httprouter.WithMeta(r, Audit{Level: "high"}).Delete("/users/:id", handler, "").
+// Groups created from it inherit the metadata, a value of the same type set later replaces the inherited one.
+func WithMeta[T any](r Router, value T) Router {
+	router, ok := r.(metaRouter)
+	if !ok {
+		panic(fmt.Sprintf("httprouter: %T does not support metadata", r))
+	}
+
+	return router.withMeta(metaKey[T]{}, value)
+}
+
+// Meta returns the metadata of type T attached to the route.
+func Meta[T any](routeInfo RouteInfo) (T, bool) {
+	value, ok := routeInfo.meta[metaKey[T]{}].(T)
+
+	return value, ok
+}
+
+// RouteMeta returns the metadata of type T attached to the route that matched the request.
+func RouteMeta[T any](ctx context.Context) (T, bool) {
+	return Meta[T](CurrentRoute(ctx))
+}
+
+func (s *scope) withMeta(key any, value any) Router {
+	group := s.child()
+
+	group.meta = make(map[any]any, len(s.meta)+1)
+	for metaKey, metaValue := range s.meta {
+		group.meta[metaKey] = metaValue
+	}
+
+	group.meta[key] = value
+
+	return group
+}
+
+func (s *scope) metaValue(key any) (any, bool) {
+	value, ok := s.meta[key]
+
+	return value, ok
+}
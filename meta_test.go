@@ -0,0 +1,73 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditMeta struct {
+	Level string
+}
+
+type ownerMeta string
+
+func TestWithMeta(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+
+	var audit auditMeta
+	var auditFound bool
+
+	handler := httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+		audit, auditFound = httprouter.RouteMeta[auditMeta](request.Context())
+
+		return nil
+	})
+
+	router.Get("/plain", handler, "plain")
+
+	admin := httprouter.WithMeta(router, ownerMeta("admin-team"))
+	admin = httprouter.WithMeta(admin, auditMeta{Level: "low"})
+
+	admin.Route("/admin", func(group httprouter.Router) {
+		group.Get("/users", handler, "users")
+		httprouter.WithMeta(group, auditMeta{Level: "high"}).Delete("/users", handler, "delete-users")
+	})
+
+	testCases := []struct {
+		routeName     string
+		expectedAudit auditMeta
+		expectedOwner ownerMeta
+		expectedFound bool
+	}{
+		{"plain", auditMeta{}, "", false},
+		{"users", auditMeta{Level: "low"}, "admin-team", true},
+		{"delete-users", auditMeta{Level: "high"}, "admin-team", true},
+	}
+
+	routeInfos := make(map[string]httprouter.RouteInfo)
+	for _, routeInfo := range router.Routes() {
+		routeInfos[routeInfo.Name] = routeInfo
+	}
+
+	for _, testCase := range testCases {
+		routeInfo := routeInfos[testCase.routeName]
+
+		audit, ok := httprouter.Meta[auditMeta](routeInfo)
+		assert.Equal(t, testCase.expectedFound, ok, testCase.routeName)
+		assert.Equal(t, testCase.expectedAudit, audit, testCase.routeName)
+
+		owner, _ := httprouter.Meta[ownerMeta](routeInfo)
+		assert.Equal(t, testCase.expectedOwner, owner, testCase.routeName)
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/admin/users", nil))
+
+	assert.True(t, auditFound)
+	assert.Equal(t, auditMeta{Level: "high"}, audit)
+}
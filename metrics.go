@@ -0,0 +1,90 @@
+package httprouter
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricLabels identify the series a request is recorded in. Route and RouteName are the pattern and the name
+// of the matched route, not the request path, so the number of series stays bounded; both are empty when no route
+// matched the request. Status is zero for in-flight requests.
+type MetricLabels struct {
+	Method    string
+	Route     string
+	RouteName string
+	Status    int
+}
+
+// MetricsSink receives the measurements of the Metrics middleware, implement it to send them to other backends.
+// Its methods are called concurrently.
+type MetricsSink interface {
+	IncInFlight(labels MetricLabels)
+	DecInFlight(labels MetricLabels)
+	ObserveRequest(labels MetricLabels, duration time.Duration, responseSize int64)
+}
+
+type MetricsConfig struct {
+	// Sink receives the measurements, it is required, see MetricsCollector.
+	Sink MetricsSink
+	// SkipPaths are request paths that are never measured, e.g. the metrics endpoint itself.
+	SkipPaths []string
+}
+
+// Metrics counts in-flight requests and observes the duration and the response size of every request by method,
+// route and status. The status of a request whose handler returned an error without writing a response is the one
+// DefaultErrorHandler answers with. Add it with Router.Pre to measure unmatched requests as well, in that case
+// in-flight requests are not labelled with the route since it is not matched yet when they start.
+func Metrics(config MetricsConfig) MiddlewareFunc {
+	if config.Sink == nil {
+		panic("httprouter: metrics sink is nil")
+	}
+
+	skipPaths := make(map[string]struct{}, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skipPaths[path] = struct{}{}
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if _, ok := skipPaths[request.URL.Path]; ok {
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			}
+
+			start := time.Now()
+			inFlightLabels := metricLabels(request, 0)
+
+			config.Sink.IncInFlight(inFlightLabels)
+			defer config.Sink.DecInFlight(inFlightLabels)
+
+			recorder := newResponseRecorder(responseWriter)
+
+			err := next.Handle(recorder, request)
+
+			config.Sink.ObserveRequest(metricLabels(request, recorder.statusFor(err)), time.Since(start), recorder.bytes)
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+func metricLabels(request *http.Request, status int) MetricLabels {
+	routeInfo := CurrentRoute(request.Context())
+
+	return MetricLabels{
+		Method:    metricMethod(request.Method),
+		Route:     routeInfo.Pattern,
+		RouteName: routeInfo.Name,
+		Status:    status,
+	}
+}
+
+// metricMethod replaces non-standard methods with OTHER, so clients can't create new series at will.
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
@@ -0,0 +1,232 @@
+package httprouter
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultDurationBuckets are the upper bounds, in seconds, of the request duration histogram.
+	DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// DefaultSizeBuckets are the upper bounds, in bytes, of the response size histogram.
+	DefaultSizeBuckets = []float64{100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000}
+)
+
+type MetricsCollectorConfig struct {
+	// Namespace is prepended to metric names, e.g. "myapp" gives myapp_http_requests_total.
+	Namespace string
+	// DurationBuckets are the upper bounds in seconds, DefaultDurationBuckets are used when it is empty.
+	DurationBuckets []float64
+	// SizeBuckets are the upper bounds in bytes, DefaultSizeBuckets are used when it is empty.
+	SizeBuckets []float64
+}
+
+// MetricsCollector is a MetricsSink that keeps the measurements in memory and serves them
+// in the Prometheus text exposition format.
+type MetricsCollector struct {
+	prefix          string
+	durationBuckets []float64
+	sizeBuckets     []float64
+
+	mu        sync.Mutex
+	requests  map[MetricLabels]uint64
+	inFlight  map[MetricLabels]int64
+	durations map[MetricLabels]*histogram
+	sizes     map[MetricLabels]*histogram
+}
+
+func NewMetricsCollector(config MetricsCollectorConfig) *MetricsCollector {
+	prefix := ""
+	if config.Namespace != "" {
+		prefix = config.Namespace + "_"
+	}
+
+	return &MetricsCollector{
+		prefix:          prefix,
+		durationBuckets: histogramBuckets(config.DurationBuckets, DefaultDurationBuckets),
+		sizeBuckets:     histogramBuckets(config.SizeBuckets, DefaultSizeBuckets),
+		requests:        make(map[MetricLabels]uint64),
+		inFlight:        make(map[MetricLabels]int64),
+		durations:       make(map[MetricLabels]*histogram),
+		sizes:           make(map[MetricLabels]*histogram),
+	}
+}
+
+func (c *MetricsCollector) IncInFlight(labels MetricLabels) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight[labels]++
+}
+
+func (c *MetricsCollector) DecInFlight(labels MetricLabels) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight[labels]--
+}
+
+func (c *MetricsCollector) ObserveRequest(labels MetricLabels, duration time.Duration, responseSize int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[labels]++
+
+	durationHistogram, ok := c.durations[labels]
+	if !ok {
+		durationHistogram = newHistogram(c.durationBuckets)
+		c.durations[labels] = durationHistogram
+	}
+
+	durationHistogram.observe(duration.Seconds())
+
+	sizeHistogram, ok := c.sizes[labels]
+	if !ok {
+		sizeHistogram = newHistogram(c.sizeBuckets)
+		c.sizes[labels] = sizeHistogram
+	}
+
+	sizeHistogram.observe(float64(responseSize))
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text exposition format,
+// register it with Router.HandleHTTP.
+func (c *MetricsCollector) ServeHTTP(responseWriter http.ResponseWriter, _ *http.Request) {
+	responseWriter.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	writer := bufio.NewWriter(responseWriter)
+
+	c.mu.Lock()
+	c.writeMetrics(writer)
+	c.mu.Unlock()
+
+	_ = writer.Flush()
+}
+
+func (c *MetricsCollector) writeMetrics(writer *bufio.Writer) {
+	name := c.prefix + "http_requests_total"
+	writeMetricHeader(writer, name, "counter", "Total number of HTTP requests.")
+
+	for _, labels := range sortedMetricLabels(c.requests) {
+		fmt.Fprintf(writer, "%s{%s} %d\n", name, formatMetricLabels(labels), c.requests[labels])
+	}
+
+	name = c.prefix + "http_requests_in_flight"
+	writeMetricHeader(writer, name, "gauge", "Number of HTTP requests being served.")
+
+	for _, labels := range sortedMetricLabels(c.inFlight) {
+		fmt.Fprintf(writer, "%s{%s} %d\n", name, formatMetricLabels(labels), c.inFlight[labels])
+	}
+
+	name = c.prefix + "http_request_duration_seconds"
+	writeMetricHeader(writer, name, "histogram", "Duration of HTTP requests in seconds.")
+
+	for _, labels := range sortedMetricLabels(c.durations) {
+		c.durations[labels].write(writer, name, formatMetricLabels(labels))
+	}
+
+	name = c.prefix + "http_response_size_bytes"
+	writeMetricHeader(writer, name, "histogram", "Size of HTTP responses in bytes.")
+
+	for _, labels := range sortedMetricLabels(c.sizes) {
+		c.sizes[labels].write(writer, name, formatMetricLabels(labels))
+	}
+}
+
+func writeMetricHeader(writer *bufio.Writer, name string, metricType string, help string) {
+	fmt.Fprintf(writer, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func sortedMetricLabels[V any](series map[MetricLabels]V) []MetricLabels {
+	labels := make([]MetricLabels, 0, len(series))
+	for label := range series {
+		labels = append(labels, label)
+	}
+
+	slices.SortFunc(labels, func(a, b MetricLabels) int {
+		return cmp.Or(
+			cmp.Compare(a.Route, b.Route),
+			cmp.Compare(a.RouteName, b.RouteName),
+			cmp.Compare(a.Method, b.Method),
+			cmp.Compare(a.Status, b.Status),
+		)
+	})
+
+	return labels
+}
+
+func formatMetricLabels(labels MetricLabels) string {
+	formatted := fmt.Sprintf(`method="%s",route="%s",route_name="%s"`,
+		escapeLabelValue(labels.Method),
+		escapeLabelValue(labels.Route),
+		escapeLabelValue(labels.RouteName),
+	)
+
+	if labels.Status != 0 {
+		formatted += `,status="` + strconv.Itoa(labels.Status) + `"`
+	}
+
+	return formatted
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func histogramBuckets(buckets []float64, defaultBuckets []float64) []float64 {
+	if len(buckets) == 0 {
+		buckets = defaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return slices.Compact(buckets)
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(value float64) {
+	if idx := sort.SearchFloat64s(h.buckets, value); idx < len(h.counts) {
+		h.counts[idx]++
+	}
+
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) write(writer *bufio.Writer, name string, labels string) {
+	var cumulative uint64
+
+	for idx, upperBound := range h.buckets {
+		cumulative += h.counts[idx]
+		fmt.Fprintf(writer, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(upperBound), cumulative)
+	}
+
+	fmt.Fprintf(writer, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+	fmt.Fprintf(writer, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+	fmt.Fprintf(writer, "%s_count{%s} %d\n", name, labels, h.count)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
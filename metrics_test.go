@@ -0,0 +1,179 @@
+package httprouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+type observation struct {
+	labels httprouter.MetricLabels
+	size   int64
+}
+
+type recordingSink struct {
+	mu           sync.Mutex
+	inFlight     []httprouter.MetricLabels
+	observations []observation
+}
+
+func (s *recordingSink) IncInFlight(labels httprouter.MetricLabels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight = append(s.inFlight, labels)
+}
+
+func (s *recordingSink) DecInFlight(httprouter.MetricLabels) {}
+
+func (s *recordingSink) ObserveRequest(labels httprouter.MetricLabels, _ time.Duration, responseSize int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.observations = append(s.observations, observation{labels: labels, size: responseSize})
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		method              string
+		path                string
+		expectedInFlight    httprouter.MetricLabels
+		expectedObservation observation
+	}{
+		{
+			name:             "matched route",
+			method:           http.MethodGet,
+			path:             "/users/42",
+			expectedInFlight: httprouter.MetricLabels{Method: http.MethodGet},
+			expectedObservation: observation{
+				labels: httprouter.MetricLabels{Method: http.MethodGet, Route: "/users/:id", RouteName: "user", Status: http.StatusOK},
+				size:   5,
+			},
+		},
+		{
+			name:             "handler error",
+			method:           http.MethodPost,
+			path:             "/fail",
+			expectedInFlight: httprouter.MetricLabels{Method: http.MethodPost},
+			expectedObservation: observation{
+				labels: httprouter.MetricLabels{Method: http.MethodPost, Route: "/fail", RouteName: "fail", Status: http.StatusTeapot},
+			},
+		},
+		{
+			name:             "unmatched route with non-standard method",
+			method:           "PURGE",
+			path:             "/missing",
+			expectedInFlight: httprouter.MetricLabels{Method: "OTHER"},
+			expectedObservation: observation{
+				labels: httprouter.MetricLabels{Method: "OTHER", Status: http.StatusNotFound},
+				size:   19,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := &recordingSink{}
+
+			router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+			router.Pre(httprouter.Metrics(httprouter.MetricsConfig{Sink: sink, SkipPaths: []string{"/metrics"}}))
+			router.Get("/users/:id", httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				_, _ = responseWriter.Write([]byte("alice"))
+
+				return nil
+			}), "user")
+			router.Post("/fail", httprouter.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+				return httprouter.NewHTTPError(http.StatusTeapot, errors.New("teapot"))
+			}), "fail")
+			router.Get("/metrics", &mockHandler{}, "")
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(testCase.method, testCase.path, nil))
+
+			assert.Equal(t, []httprouter.MetricLabels{testCase.expectedInFlight}, sink.inFlight)
+			assert.Equal(t, []observation{testCase.expectedObservation}, sink.observations)
+		})
+	}
+}
+
+func TestMetrics_NilSink(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "httprouter: metrics sink is nil", func() {
+		httprouter.Metrics(httprouter.MetricsConfig{})
+	})
+}
+
+func TestMetricsCollector_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	collector := httprouter.NewMetricsCollector(httprouter.MetricsCollectorConfig{
+		Namespace:       "app",
+		DurationBuckets: []float64{1, 0.1},
+		SizeBuckets:     []float64{100},
+	})
+
+	userLabels := httprouter.MetricLabels{Method: http.MethodGet, Route: "/users/:id", RouteName: "user"}
+	itemLabels := httprouter.MetricLabels{Method: http.MethodGet, Route: `/items/"x"`, RouteName: "item"}
+
+	collector.IncInFlight(userLabels)
+	collector.IncInFlight(userLabels)
+	collector.DecInFlight(userLabels)
+
+	userLabels.Status = http.StatusOK
+	collector.ObserveRequest(userLabels, 50*time.Millisecond, 10)
+	collector.ObserveRequest(userLabels, 2*time.Second, 1000)
+
+	itemLabels.Status = http.StatusNotFound
+	collector.ObserveRequest(itemLabels, 500*time.Millisecond, 0)
+
+	expected := `# HELP app_http_requests_total Total number of HTTP requests.
+# TYPE app_http_requests_total counter
+app_http_requests_total{method="GET",route="/items/\"x\"",route_name="item",status="404"} 1
+app_http_requests_total{method="GET",route="/users/:id",route_name="user",status="200"} 2
+# HELP app_http_requests_in_flight Number of HTTP requests being served.
+# TYPE app_http_requests_in_flight gauge
+app_http_requests_in_flight{method="GET",route="/users/:id",route_name="user"} 1
+# HELP app_http_request_duration_seconds Duration of HTTP requests in seconds.
+# TYPE app_http_request_duration_seconds histogram
+app_http_request_duration_seconds_bucket{method="GET",route="/items/\"x\"",route_name="item",status="404",le="0.1"} 0
+app_http_request_duration_seconds_bucket{method="GET",route="/items/\"x\"",route_name="item",status="404",le="1"} 1
+app_http_request_duration_seconds_bucket{method="GET",route="/items/\"x\"",route_name="item",status="404",le="+Inf"} 1
+app_http_request_duration_seconds_sum{method="GET",route="/items/\"x\"",route_name="item",status="404"} 0.5
+app_http_request_duration_seconds_count{method="GET",route="/items/\"x\"",route_name="item",status="404"} 1
+app_http_request_duration_seconds_bucket{method="GET",route="/users/:id",route_name="user",status="200",le="0.1"} 1
+app_http_request_duration_seconds_bucket{method="GET",route="/users/:id",route_name="user",status="200",le="1"} 1
+app_http_request_duration_seconds_bucket{method="GET",route="/users/:id",route_name="user",status="200",le="+Inf"} 2
+app_http_request_duration_seconds_sum{method="GET",route="/users/:id",route_name="user",status="200"} 2.05
+app_http_request_duration_seconds_count{method="GET",route="/users/:id",route_name="user",status="200"} 2
+# HELP app_http_response_size_bytes Size of HTTP responses in bytes.
+# TYPE app_http_response_size_bytes histogram
+app_http_response_size_bytes_bucket{method="GET",route="/items/\"x\"",route_name="item",status="404",le="100"} 1
+app_http_response_size_bytes_bucket{method="GET",route="/items/\"x\"",route_name="item",status="404",le="+Inf"} 1
+app_http_response_size_bytes_sum{method="GET",route="/items/\"x\"",route_name="item",status="404"} 0
+app_http_response_size_bytes_count{method="GET",route="/items/\"x\"",route_name="item",status="404"} 1
+app_http_response_size_bytes_bucket{method="GET",route="/users/:id",route_name="user",status="200",le="100"} 1
+app_http_response_size_bytes_bucket{method="GET",route="/users/:id",route_name="user",status="200",le="+Inf"} 2
+app_http_response_size_bytes_sum{method="GET",route="/users/:id",route_name="user",status="200"} 1010
+app_http_response_size_bytes_count{method="GET",route="/users/:id",route_name="user",status="200"} 2
+`
+
+	responseRecorder := httptest.NewRecorder()
+	collector.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", responseRecorder.Header().Get("Content-Type"))
+	assert.Equal(t, expected, responseRecorder.Body.String())
+}
@@ -0,0 +1,133 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MountRoute matches every request whose path starts with Prefix and passes it to Handler with the prefix stripped.
+// Prefix segments may be :name or {name} params.
+type MountRoute struct {
+	Prefix  string
+	Handler Handler
+	Name    string
+}
+
+func (mountRoute *MountRoute) Match(request *http.Request) (RouteMatch, error) {
+	var routeMatch RouteMatch
+
+	params, ok := matchPathPrefix(mountRoute.Prefix, request.URL.Path)
+	if !ok {
+		return routeMatch, ErrPathMismatch
+	}
+
+	routeMatch.Handler = mountRoute.Handler
+	routeMatch.Params = params
+	routeMatch.RouteName = mountRoute.Name
+
+	return routeMatch, nil
+}
+
+// matchPathPrefix reports whether the path is equal to the prefix or continues it with a new segment,
+// :name and {name} prefix segments match any non-empty path segment and are returned as params.
+func matchPathPrefix(prefix string, path string) (RouteParams, bool) {
+	prefixSegments := pathSegments(prefix)
+	segments := pathSegments(path)
+
+	if len(segments) < len(prefixSegments) {
+		return nil, false
+	}
+
+	params := make(RouteParams)
+
+	for idx, prefixSegment := range prefixSegments {
+		paramName, isParam := prefixParamName(prefixSegment)
+
+		switch {
+		case isParam && segments[idx] != "":
+			params[paramName] = segments[idx]
+		case isParam || prefixSegment != segments[idx]:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func prefixParamName(segment string) (string, bool) {
+	if strings.HasPrefix(segment, ":") {
+		return segment[1:], true
+	}
+
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+
+	return "", false
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// mountHandler strips the mount prefix from the request path and serves the request with the mounted handler.
+type mountHandler struct {
+	router         *router
+	handler        http.Handler
+	prefix         string
+	prefixSegments int
+}
+
+func (h *mountHandler) Handle(responseWriter http.ResponseWriter, request *http.Request) error {
+	ctx := request.Context()
+	if _, ok := ctx.Value(originalPathKey).(string); !ok {
+		ctx = context.WithValue(ctx, originalPathKey, request.URL.Path)
+	}
+
+	// patterns of a mounted router are reported by CurrentRoute with the mount prefix
+	parentPattern, _ := ctx.Value(mountPatternKey).(string)
+	ctx = context.WithValue(ctx, mountPatternKey, strings.Trim(parentPattern+"/"+strings.Trim(h.prefix, "/"), "/"))
+
+	mountedURL := *request.URL
+	mountedURL.Path = stripPathSegments(request.URL.Path, h.prefixSegments)
+
+	if h.router.UseRawPath {
+		// the prefix matched the escaped path, an encoded slash in a prefix param is part of a single segment
+		escapedPath := stripPathSegments(request.URL.EscapedPath(), h.prefixSegments)
+		if path, err := url.PathUnescape(escapedPath); err == nil {
+			mountedURL.Path = path
+			mountedURL.RawPath = escapedPath
+		}
+	} else if request.URL.RawPath != "" {
+		mountedURL.RawPath = stripPathSegments(request.URL.RawPath, h.prefixSegments)
+	}
+
+	mountedRequest := request.WithContext(ctx)
+	mountedRequest.URL = &mountedURL
+
+	h.handler.ServeHTTP(responseWriter, mountedRequest)
+
+	return nil
+}
+
+func stripPathSegments(path string, count int) string {
+	path = strings.TrimPrefix(path, "/")
+
+	for ; count > 0; count-- {
+		idx := strings.Index(path, "/")
+		if idx < 0 {
+			return "/"
+		}
+
+		path = path[idx+1:]
+	}
+
+	return "/" + path
+}
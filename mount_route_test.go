@@ -0,0 +1,145 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountRoute_Match(t *testing.T) {
+	t.Parallel()
+
+	route := &httprouter.MountRoute{
+		Prefix:  "/tenants/:tid/api",
+		Handler: &mockHandler{},
+		Name:    "api",
+	}
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedParams httprouter.RouteParams
+		expectedErr    error
+	}{
+		{"Prefix", "/tenants/1/api", httprouter.RouteParams{"tid": "1"}, nil},
+		{"PrefixWithTrailingSlash", "/tenants/1/api/", httprouter.RouteParams{"tid": "1"}, nil},
+		{"SubPath", "/tenants/1/api/users/2", httprouter.RouteParams{"tid": "1"}, nil},
+		{"PartialSegment", "/tenants/1/apix", nil, httprouter.ErrPathMismatch},
+		{"ShortPath", "/tenants/1", nil, httprouter.ErrPathMismatch},
+		{"EmptyParam", "/tenants//api", nil, httprouter.ErrPathMismatch},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, method := range []string{http.MethodGet, http.MethodPost} {
+				routeMatch, err := route.Match(httptest.NewRequest(method, testCase.path, nil))
+				if testCase.expectedErr != nil {
+					assert.ErrorIs(t, err, testCase.expectedErr)
+					assert.Empty(t, routeMatch, "RouteMatch should be empty")
+
+					continue
+				}
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, testCase.expectedParams, routeMatch.Params)
+					assert.Equal(t, "api", routeMatch.RouteName)
+				}
+			}
+		})
+	}
+}
+
+func TestRouter_Mount_Router(t *testing.T) {
+	t.Parallel()
+
+	var path, originalPath, tenantID, userID string
+
+	usersRouter := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	usersRouter.Get("/users/:uid", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+		path = request.URL.Path
+		originalPath = httprouter.OriginalPath(request.Context())
+		tenantID = httprouter.RouteParam(request.Context(), "tid")
+		userID = request.PathValue("uid")
+
+		return nil
+	}), "")
+
+	router := httprouter.New()
+	router.Mount("/tenants/:tid", usersRouter)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tenants/7/users/42", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "/users/42", path)
+	assert.Equal(t, "/tenants/7/users/42", originalPath)
+	assert.Equal(t, "7", tenantID)
+	assert.Equal(t, "42", userID)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tenants/7/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "Status code mismatch")
+}
+
+func TestRouter_Mount_HTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	var path string
+
+	router := httprouter.New()
+
+	var middlewareCalled bool
+
+	router.Group(func(group httprouter.Router) {
+		group.WithPrefix("debug")
+		group.Use(func(next httprouter.Handler) httprouter.Handler {
+			return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+				middlewareCalled = true
+
+				return next.Handle(responseWriter, request) //nolint:wrapcheck
+			})
+		})
+		group.Mount("/pprof", http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+			path = request.URL.Path
+		}))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/pprof/heap", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "/heap", path)
+	assert.True(t, middlewareCalled, "Expected group middleware to be called")
+}
+
+func TestRouter_Mount_UseRawPath(t *testing.T) {
+	t.Parallel()
+
+	var path, rawPath, tenantID string
+
+	child := http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		path = request.URL.Path
+		rawPath = request.URL.EscapedPath()
+		tenantID = httprouter.RouteParam(request.Context(), "tid")
+	})
+
+	router := httprouter.New()
+	router.UseRawPath = true
+	router.Mount("/tenants/:tid", child)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tenants/a%2Fb/x%20y", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "/x y", path)
+	assert.Equal(t, "/x%20y", rawPath)
+	assert.Equal(t, "a/b", tenantID)
+}
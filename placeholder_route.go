@@ -8,16 +8,28 @@ type PlaceholderRoute struct {
 	Methods []string
 	Tree    *tree
 	Name    string
+	// Handler overrides the handler stored in the tree, so routes with the same path and different methods
+	// keep their own handlers.
+	Handler Handler
+
+	// node is the tree node of the route path, when set the route matches only paths that end at this node,
+	// otherwise it matches any path in the tree.
+	node *node
 }
 
 func (route *PlaceholderRoute) Match(request *http.Request) (RouteMatch, error) {
 	var routeMatch RouteMatch
 
-	handler, params := route.Tree.Search(request.URL.Path)
-	if handler == nil {
+	node, params := route.Tree.search(request.URL.Path)
+	if node == nil || node.Value == nil || (route.node != nil && node != route.node) {
 		return routeMatch, ErrPathMismatch
 	}
 
+	handler := node.Value
+	if route.Handler != nil {
+		handler = route.Handler
+	}
+
 	if !contains(route.Methods, request.Method) {
 		return routeMatch, ErrMethodNotAllowed
 	}
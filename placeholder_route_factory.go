@@ -25,7 +25,7 @@ func (f *placeholderRouteFactory) Handles(path string) bool {
 }
 
 func (f *placeholderRouteFactory) CreateRoute(path string, methods []string, handler Handler, routeName string) Route {
-	_ = f.tree.Insert(path, handler)
+	node := f.tree.Insert(path, handler)
 
 	if routeName == "" {
 		routeName = path
@@ -35,5 +35,7 @@ func (f *placeholderRouteFactory) CreateRoute(path string, methods []string, han
 		Methods: methods,
 		Tree:    f.tree,
 		Name:    routeName,
+		Handler: handler,
+		node:    node,
 	}
 }
@@ -3,6 +3,7 @@ package httprouter_test
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/inbugay1/httprouter"
@@ -100,3 +101,43 @@ func TestPlaceholderRouteMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaceholderRoute_SamePathDifferentMethods(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+
+	router.Get("/users/:id", statusHandler(http.StatusOK, "get user"), "get-user")
+	router.Delete("/users/:id", statusHandler(http.StatusNoContent, ""), "delete-user")
+	router.Get("/orders/:id", statusHandler(http.StatusOK, "get order"), "get-order")
+
+	testCases := []struct {
+		method            string
+		path              string
+		expectedStatus    int
+		expectedRouteName string
+	}{
+		{http.MethodGet, "/users/1", http.StatusOK, "get-user"},
+		{http.MethodDelete, "/users/1", http.StatusNoContent, "delete-user"},
+		{http.MethodGet, "/orders/1", http.StatusOK, "get-order"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.method+" "+testCase.path, func(t *testing.T) {
+			t.Parallel()
+
+			request, _ := http.NewRequestWithContext(context.Background(), testCase.method, testCase.path, nil)
+
+			routeMatch, err := router.Match(request)
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expectedRouteName, routeMatch.RouteName)
+
+				recorder := httptest.NewRecorder()
+				_ = routeMatch.Handler.Handle(recorder, request)
+				assert.Equal(t, testCase.expectedStatus, recorder.Code, "Status code mismatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package httprouter
+
+import (
+	"net/http"
+)
+
+// probedMethods are the methods AllowedMethods checks the routes for.
+var probedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// AllowedMethods returns the methods the router serving the request has routes for at the request path,
+// e.g. to answer OPTIONS requests. It returns nil outside of a router.
+func AllowedMethods(request *http.Request) []string {
+	router, ok := request.Context().Value(routerKey).(*router)
+	if !ok {
+		return nil
+	}
+
+	return router.allowedMethods(request)
+}
+
+func (r *router) allowedMethods(request *http.Request) []string {
+	var methods []string
+
+	for _, method := range probedMethods {
+		probe := *request
+		probe.Method = method
+
+		if _, _, err := r.match(&probe); err == nil {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+func isPreflightRequest(request *http.Request) bool {
+	return request.Method == http.MethodOptions &&
+		request.Header.Get("Origin") != "" &&
+		request.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// matchPreflight matches a CORS preflight request of a path without an OPTIONS route to the route of the requested
+// method. The route handler is replaced with the scope middleware of the route, so a CORS middleware added with Use
+// answers preflights of its scope routes. A preflight no CORS middleware answered is answered with 405 Method Not
+// Allowed, like any OPTIONS request of a path without an OPTIONS route.
+func (r *router) matchPreflight(request *http.Request) (RouteMatch, RouteInfo, error) {
+	probe := *request
+	probe.Method = request.Header.Get("Access-Control-Request-Method")
+
+	idx, routeMatch, err := r.matchRoute(&probe)
+	if err != nil {
+		return RouteMatch{}, RouteInfo{}, ErrMethodNotAllowed
+	}
+
+	var handler Handler = HandlerFunc(r.methodNotAllowed)
+
+	if middleware := r.routeMiddlewares[idx]; middleware != nil {
+		handler = middleware(handler)
+	}
+
+	routeMatch.Handler = handler
+
+	return routeMatch, r.routeInfos[idx], nil
+}
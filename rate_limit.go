@@ -0,0 +1,135 @@
+package httprouter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RateLimitAlgorithm int
+
+const (
+	// RateLimitTokenBucket allows bursts of up to Limit requests and refills Limit tokens evenly over Window.
+	RateLimitTokenBucket RateLimitAlgorithm = iota
+	// RateLimitSlidingWindow allows Limit requests in any Window, estimated from the counts of the current
+	// and the previous fixed window.
+	RateLimitSlidingWindow
+)
+
+// RateLimitRule is the limit a RateLimitStore applies to a key.
+type RateLimitRule struct {
+	Algorithm RateLimitAlgorithm
+	Limit     int
+	Window    time.Duration
+}
+
+// RateLimitResult is the outcome of taking a request from a key's quota.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the quota is fully available again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed, it is zero for allowed requests.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps the state of rate limited keys, implement it to share limits between instances,
+// e.g. with Redis. Take must be safe for concurrent use.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error)
+}
+
+type RateLimitConfig struct {
+	// Limit is the number of requests allowed per Window, it is required.
+	Limit int
+	// Window is the period of the limit, one minute is used when it is zero.
+	Window    time.Duration
+	Algorithm RateLimitAlgorithm
+	// Key returns the key requests are counted by, RateLimitByIP is used when it is nil.
+	Key func(request *http.Request) string
+	// PerRoute counts the requests of every matched route separately, otherwise all routes the middleware is applied to
+	// share the quota of a key. It has no effect when the middleware is added with Router.Pre.
+	PerRoute bool
+	// Name separates the keys of the limit from the keys of other limits in a shared Store.
+	Name string
+	// Store keeps the state of keys, a new MemoryRateLimitStore with default settings is used when it is nil.
+	Store RateLimitStore
+}
+
+// RateLimitByIP counts requests by the client IP, as resolved by ProxyHeaders behind proxies.
+func RateLimitByIP(request *http.Request) string {
+	return ClientIP(request)
+}
+
+// RateLimitByHeader counts requests by the value of the header, e.g. an API key,
+// requests without the header share one quota.
+func RateLimitByHeader(name string) func(request *http.Request) string {
+	return func(request *http.Request) string {
+		return request.Header.Get(name)
+	}
+}
+
+// RateLimit limits the number of requests per key. Every response carries the RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers of the IETF RateLimit header fields draft, requests over the limit
+// are answered with 429 Too Many Requests and a Retry-After header by the router error handler.
+// Apply it to a group with Use or to a single route with With.
+func RateLimit(config RateLimitConfig) MiddlewareFunc {
+	if config.Limit <= 0 {
+		panic("httprouter: rate limit must be positive")
+	}
+
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+
+	if config.Key == nil {
+		config.Key = RateLimitByIP
+	}
+
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore(MemoryRateLimitStoreConfig{})
+	}
+
+	rule := RateLimitRule{
+		Algorithm: config.Algorithm,
+		Limit:     config.Limit,
+		Window:    config.Window,
+	}
+	policy := fmt.Sprintf("%d;w=%d", config.Limit, int(math.Ceil(config.Window.Seconds())))
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			key := config.Key(request)
+			if config.PerRoute {
+				key = CurrentRoute(request.Context()).Name + "|" + key
+			}
+
+			result, err := config.Store.Take(request.Context(), config.Name+"|"+key, rule)
+			if err != nil {
+				return fmt.Errorf("httprouter: rate limit store: %w", err)
+			}
+
+			header := responseWriter.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+			header.Set("RateLimit-Policy", policy)
+
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+
+				return NewHTTPError(http.StatusTooManyRequests, ErrRateLimited)
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
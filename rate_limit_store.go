@@ -0,0 +1,192 @@
+package httprouter
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type MemoryRateLimitStoreConfig struct {
+	// MaxKeys is the number of keys kept, the least recently used key is evicted to make room for a new one.
+	// 100000 is used when it is zero.
+	MaxKeys int
+	// Now returns the current time, time.Now is used when it is nil.
+	Now func() time.Time
+}
+
+// MemoryRateLimitStore is a RateLimitStore that keeps the state of keys in memory. Keys whose quota is fully
+// available again are evicted, as well as the least recently used keys once there are more than MaxKeys.
+type MemoryRateLimitStore struct {
+	maxKeys int
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type rateLimitEntry struct {
+	key       string
+	expiresAt time.Time
+
+	// token bucket state
+	tokens     float64
+	refilledAt time.Time
+
+	// sliding window state
+	windowStart   time.Time
+	currentCount  int
+	previousCount int
+}
+
+func NewMemoryRateLimitStore(config MemoryRateLimitStoreConfig) *MemoryRateLimitStore {
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = 100_000
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &MemoryRateLimitStore{
+		maxKeys: config.MaxKeys,
+		now:     config.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, rule RateLimitRule) (RateLimitResult, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired(now)
+
+	element, ok := s.entries[key]
+	if ok {
+		s.lru.MoveToFront(element)
+	} else {
+		element = s.lru.PushFront(&rateLimitEntry{
+			key:         key,
+			tokens:      float64(rule.Limit),
+			refilledAt:  now,
+			windowStart: now.Truncate(rule.Window),
+		})
+		s.entries[key] = element
+
+		if s.lru.Len() > s.maxKeys {
+			s.remove(s.lru.Back())
+		}
+	}
+
+	entry := element.Value.(*rateLimitEntry)
+
+	if rule.Algorithm == RateLimitSlidingWindow {
+		return entry.takeSlidingWindow(rule, now), nil
+	}
+
+	return entry.takeToken(rule, now), nil
+}
+
+// evictExpired removes keys from the least recently used end while their quota is fully available again.
+func (s *MemoryRateLimitStore) evictExpired(now time.Time) {
+	for element := s.lru.Back(); element != nil; element = s.lru.Back() {
+		if element.Value.(*rateLimitEntry).expiresAt.After(now) {
+			return
+		}
+
+		s.remove(element)
+	}
+}
+
+func (s *MemoryRateLimitStore) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*rateLimitEntry).key)
+}
+
+func (e *rateLimitEntry) takeToken(rule RateLimitRule, now time.Time) RateLimitResult {
+	capacity := float64(rule.Limit)
+	tokensPerSecond := capacity / rule.Window.Seconds()
+
+	e.tokens = math.Min(capacity, e.tokens+now.Sub(e.refilledAt).Seconds()*tokensPerSecond)
+	e.refilledAt = now
+
+	result := RateLimitResult{Limit: rule.Limit}
+
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - e.tokens) / tokensPerSecond)
+	}
+
+	result.Remaining = int(e.tokens)
+	result.ResetAfter = secondsDuration((capacity - e.tokens) / tokensPerSecond)
+	e.expiresAt = now.Add(result.ResetAfter)
+
+	return result
+}
+
+func (e *rateLimitEntry) takeSlidingWindow(rule RateLimitRule, now time.Time) RateLimitResult {
+	windowStart := now.Truncate(rule.Window)
+
+	switch elapsedWindows := int(windowStart.Sub(e.windowStart) / rule.Window); {
+	case elapsedWindows == 1:
+		e.previousCount, e.currentCount = e.currentCount, 0
+	case elapsedWindows > 1:
+		e.previousCount, e.currentCount = 0, 0
+	}
+
+	e.windowStart = windowStart
+
+	elapsed := now.Sub(windowStart)
+	previousWeight := 1 - elapsed.Seconds()/rule.Window.Seconds()
+	estimate := float64(e.previousCount)*previousWeight + float64(e.currentCount)
+
+	result := RateLimitResult{Limit: rule.Limit}
+
+	if estimate+1 <= float64(rule.Limit) {
+		e.currentCount++
+		estimate++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = e.slidingWindowRetryAfter(rule, elapsed)
+	}
+
+	result.Remaining = max(0, rule.Limit-int(math.Ceil(estimate)))
+
+	// the previous window stops counting at the end of the current one, the current one at the end of the next one
+	result.ResetAfter = rule.Window - elapsed
+	if e.currentCount > 0 {
+		result.ResetAfter += rule.Window
+	}
+
+	e.expiresAt = now.Add(result.ResetAfter)
+
+	return result
+}
+
+// slidingWindowRetryAfter returns the time until the estimate leaves room for one more request.
+func (e *rateLimitEntry) slidingWindowRetryAfter(rule RateLimitRule, elapsed time.Duration) time.Duration {
+	free := float64(rule.Limit - 1)
+
+	// room is made by the previous window sliding out of the current one
+	if float64(e.currentCount) <= free && e.previousCount > 0 {
+		fraction := 1 - (free-float64(e.currentCount))/float64(e.previousCount)
+
+		return time.Duration(fraction*float64(rule.Window)) - elapsed
+	}
+
+	// room is made by the current window sliding out of the next one
+	fraction := math.Max(0, 1-free/float64(e.currentCount))
+
+	return rule.Window - elapsed + time.Duration(fraction*float64(rule.Window))
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,262 @@
+package httprouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Use(httprouter.RateLimit(httprouter.RateLimitConfig{
+		Limit: 2,
+		Store: httprouter.NewMemoryRateLimitStore(httprouter.MemoryRateLimitStoreConfig{Now: newFakeClock().Now}),
+	}))
+	router.Get("/users", &mockHandler{}, "")
+
+	expectedResponses := []struct {
+		status  int
+		headers map[string]string
+	}{
+		{
+			status: http.StatusOK,
+			headers: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "1",
+				"RateLimit-Reset":     "30",
+				"RateLimit-Policy":    "2;w=60",
+				"Retry-After":         "",
+			},
+		},
+		{
+			status: http.StatusOK,
+			headers: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"Retry-After":         "",
+			},
+		},
+		{
+			status: http.StatusTooManyRequests,
+			headers: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"Retry-After":         "30",
+			},
+		},
+	}
+
+	for _, expected := range expectedResponses {
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		assert.Equal(t, expected.status, responseRecorder.Code)
+
+		for headerName, headerValue := range expected.headers {
+			assert.Equal(t, headerValue, responseRecorder.Header().Get(headerName), headerName)
+		}
+	}
+
+	// another client has its own quota
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request.RemoteAddr = "192.0.2.2:1234"
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		config         httprouter.RateLimitConfig
+		requests       [][2]string
+		expectedStatus []int
+	}{
+		{
+			name:           "group quota is shared by its routes",
+			config:         httprouter.RateLimitConfig{Limit: 1},
+			requests:       [][2]string{{"/api/users", ""}, {"/api/items", ""}},
+			expectedStatus: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "per route quota",
+			config:         httprouter.RateLimitConfig{Limit: 1, PerRoute: true},
+			requests:       [][2]string{{"/api/users", ""}, {"/api/items", ""}, {"/api/items", ""}},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "per API key quota",
+			config: httprouter.RateLimitConfig{
+				Limit:     1,
+				Algorithm: httprouter.RateLimitSlidingWindow,
+				Key:       httprouter.RateLimitByHeader("X-API-Key"),
+			},
+			requests:       [][2]string{{"/api/users", "a"}, {"/api/users", "b"}, {"/api/users", "a"}},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.Get("/health", &mockHandler{}, "")
+			router.Route("/api", func(r httprouter.Router) {
+				r.Use(httprouter.RateLimit(testCase.config))
+				r.Get("/users", &mockHandler{}, "users")
+				r.Get("/items", &mockHandler{}, "items")
+			})
+
+			for idx, requestData := range testCase.requests {
+				request := httptest.NewRequest(http.MethodGet, requestData[0], nil)
+				request.Header.Set("X-API-Key", requestData[1])
+
+				responseRecorder := httptest.NewRecorder()
+				router.ServeHTTP(responseRecorder, request)
+
+				assert.Equal(t, testCase.expectedStatus[idx], responseRecorder.Code, requestData)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			assert.Equal(t, http.StatusOK, responseRecorder.Code)
+			assert.Empty(t, responseRecorder.Header().Get("RateLimit-Limit"))
+		})
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	t.Parallel()
+
+	type take struct {
+		advance  time.Duration
+		expected httprouter.RateLimitResult
+	}
+
+	testCases := []struct {
+		name  string
+		rule  httprouter.RateLimitRule
+		takes []take
+	}{
+		{
+			name: "token bucket",
+			rule: httprouter.RateLimitRule{Algorithm: httprouter.RateLimitTokenBucket, Limit: 2, Window: time.Minute},
+			takes: []take{
+				{expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 30 * time.Second}},
+				{expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute}},
+				{
+					advance:  15 * time.Second,
+					expected: httprouter.RateLimitResult{Limit: 2, ResetAfter: 45 * time.Second, RetryAfter: 15 * time.Second},
+				},
+				{
+					advance:  15 * time.Second,
+					expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute},
+				},
+			},
+		},
+		{
+			name: "sliding window",
+			rule: httprouter.RateLimitRule{Algorithm: httprouter.RateLimitSlidingWindow, Limit: 2, Window: time.Minute},
+			takes: []take{
+				{
+					advance:  10 * time.Second,
+					expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 110 * time.Second},
+				},
+				{expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 110 * time.Second}},
+				{
+					expected: httprouter.RateLimitResult{Limit: 2, ResetAfter: 110 * time.Second, RetryAfter: 80 * time.Second},
+				},
+				{
+					advance:  80 * time.Second,
+					expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 90 * time.Second},
+				},
+				{
+					expected: httprouter.RateLimitResult{Limit: 2, ResetAfter: 90 * time.Second, RetryAfter: 30 * time.Second},
+				},
+				{
+					advance:  30 * time.Second,
+					expected: httprouter.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 2 * time.Minute},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock()
+			store := httprouter.NewMemoryRateLimitStore(httprouter.MemoryRateLimitStoreConfig{Now: clock.Now})
+
+			for idx, take := range testCase.takes {
+				clock.Advance(take.advance)
+
+				result, err := store.Take(context.Background(), "key", testCase.rule)
+				require.NoError(t, err)
+
+				assert.Equal(t, take.expected, result, idx)
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_MaxKeys(t *testing.T) {
+	t.Parallel()
+
+	store := httprouter.NewMemoryRateLimitStore(httprouter.MemoryRateLimitStoreConfig{MaxKeys: 1})
+	rule := httprouter.RateLimitRule{Limit: 1, Window: time.Hour}
+
+	for _, key := range []string{"a", "b", "a"} {
+		result, err := store.Take(context.Background(), key, rule)
+		require.NoError(t, err)
+
+		assert.True(t, result.Allowed, key)
+	}
+
+	result, err := store.Take(context.Background(), "a", rule)
+	require.NoError(t, err)
+
+	assert.False(t, result.Allowed)
+}
@@ -0,0 +1,146 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// EncodedSlashPolicy defines what happens to an encoded slash (%2F) found in a route param
+// when the router matches against the escaped path.
+type EncodedSlashPolicy int
+
+const (
+	// EncodedSlashDecode unescapes %2F to "/" like any other escape sequence.
+	EncodedSlashDecode EncodedSlashPolicy = iota
+	// EncodedSlashKeep leaves %2F in the param as is, while other escape sequences are unescaped.
+	EncodedSlashKeep
+	// EncodedSlashReject fails the match with ErrInvalidPathEscape, which is answered with 400 Bad Request.
+	EncodedSlashReject
+)
+
+// InvalidEscapePolicy defines what happens to a route param that is not a valid escaped string.
+type InvalidEscapePolicy int
+
+const (
+	// InvalidEscapeReject fails the match with ErrInvalidPathEscape, which is answered with 400 Bad Request.
+	InvalidEscapeReject InvalidEscapePolicy = iota
+	// InvalidEscapePassThrough passes the param to the handler as it appears in the path.
+	InvalidEscapePassThrough
+)
+
+// rawPathRequest returns a shallow copy of the request whose URL.Path holds the escaped path decoded except for
+// encoded slashes and percent signs, so an encoded slash stays within its segment while literal, placeholder and regex
+// routes registered with decoded text, e.g. "/café", still match.
+func rawPathRequest(request *http.Request) *http.Request {
+	matchPath := decodePathSegments(request.URL.EscapedPath())
+	if matchPath == request.URL.Path {
+		return request
+	}
+
+	matchURL := *request.URL
+	matchURL.Path = matchPath
+	matchURL.RawPath = ""
+
+	matchRequest := *request
+	matchRequest.URL = &matchURL
+
+	return &matchRequest
+}
+
+// decodePathSegments decodes the escape sequences of the escaped path except %2F and %25, which are upper-cased.
+func decodePathSegments(escapedPath string) string {
+	if !strings.Contains(escapedPath, "%") {
+		return escapedPath
+	}
+
+	var path strings.Builder
+
+	for idx := 0; idx < len(escapedPath); idx++ {
+		if escapedPath[idx] == '%' && idx+2 < len(escapedPath) {
+			escape := strings.ToUpper(escapedPath[idx : idx+3])
+
+			decoded, err := url.PathUnescape(escape)
+			if err == nil {
+				if decoded == "/" || decoded == "%" {
+					path.WriteString(escape)
+				} else {
+					path.WriteString(decoded)
+				}
+
+				idx += 2
+
+				continue
+			}
+		}
+
+		path.WriteByte(escapedPath[idx])
+	}
+
+	return path.String()
+}
+
+func (r *router) unescapeRouteParams(params RouteParams) (RouteParams, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	unescapedParams := make(RouteParams, len(params))
+
+	for paramName, paramValue := range params {
+		unescapedValue, err := r.unescapeRouteParam(paramValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: param %q", err, paramName)
+		}
+
+		unescapedParams[paramName] = unescapedValue
+	}
+
+	return unescapedParams, nil
+}
+
+func (r *router) unescapeRouteParam(value string) (string, error) {
+	if !strings.Contains(value, "%") {
+		return value, nil
+	}
+
+	if containsEncodedSlash(value) {
+		switch r.EncodedSlashPolicy {
+		case EncodedSlashReject:
+			return "", fmt.Errorf("%w: encoded slash", ErrInvalidPathEscape)
+		case EncodedSlashKeep:
+			parts := splitEncodedSlash(value)
+			for i, part := range parts {
+				unescapedPart, err := r.unescapeRouteParam(part)
+				if err != nil {
+					return "", err
+				}
+
+				parts[i] = unescapedPart
+			}
+
+			return strings.Join(parts, "%2F"), nil
+		case EncodedSlashDecode:
+		}
+	}
+
+	unescapedValue, err := url.PathUnescape(value)
+	if err != nil {
+		if r.InvalidEscapePolicy == InvalidEscapePassThrough {
+			return value, nil
+		}
+
+		return "", fmt.Errorf("%w: %s", ErrInvalidPathEscape, err.Error())
+	}
+
+	return unescapedValue, nil
+}
+
+func containsEncodedSlash(value string) bool {
+	return strings.Contains(value, "%2F") || strings.Contains(value, "%2f")
+}
+
+func splitEncodedSlash(value string) []string {
+	return strings.Split(strings.ReplaceAll(value, "%2f", "%2F"), "%2F")
+}
@@ -0,0 +1,206 @@
+package httprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:funlen
+func TestRouter_UseRawPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		routeFactory        RouteFactory
+		routePath           string
+		requestPath         string
+		encodedSlashPolicy  EncodedSlashPolicy
+		invalidEscapePolicy InvalidEscapePolicy
+		expectedParams      RouteParams
+		expectedErr         error
+	}{
+		{
+			name:           "PlaceholderEncodedSlashDecoded",
+			routeFactory:   NewPlaceholderRouteFactory(),
+			routePath:      "/items/:id",
+			requestPath:    "/items/a%2Fb",
+			expectedParams: RouteParams{"id": "a/b"},
+		},
+		{
+			name:           "RegexEncodedSlashDecoded",
+			routeFactory:   NewRegexRouteFactory(),
+			routePath:      `/items/{id:[\w% ]+}`,
+			requestPath:    "/items/a%2Fb%20c",
+			expectedParams: RouteParams{"id": "a/b c"},
+		},
+		{
+			name:               "EncodedSlashKept",
+			routeFactory:       NewPlaceholderRouteFactory(),
+			routePath:          "/items/:id",
+			requestPath:        "/items/a%2fb%20c",
+			encodedSlashPolicy: EncodedSlashKeep,
+			expectedParams:     RouteParams{"id": "a%2Fb c"},
+		},
+		{
+			name:               "EncodedSlashRejected",
+			routeFactory:       NewPlaceholderRouteFactory(),
+			routePath:          "/items/:id",
+			requestPath:        "/items/a%2Fb",
+			encodedSlashPolicy: EncodedSlashReject,
+			expectedErr:        ErrInvalidPathEscape,
+		},
+		{
+			name:           "LiteralEscapedPath",
+			routeFactory:   NewLiteralRouteFactory(),
+			routePath:      "/items/a%2Fb",
+			requestPath:    "/items/a%2Fb",
+			expectedParams: nil,
+		},
+		{
+			name:           "LiteralNonASCII",
+			routeFactory:   NewLiteralRouteFactory(),
+			routePath:      "/café",
+			requestPath:    "/caf%C3%A9",
+			expectedParams: nil,
+		},
+		{
+			name:           "LiteralSpace",
+			routeFactory:   NewLiteralRouteFactory(),
+			routePath:      "/hello world",
+			requestPath:    "/hello%20world",
+			expectedParams: nil,
+		},
+		{
+			name:           "RegexNonASCII",
+			routeFactory:   NewRegexRouteFactory(),
+			routePath:      `/café/{id:\d+}`,
+			requestPath:    "/caf%c3%a9/7",
+			expectedParams: RouteParams{"id": "7"},
+		},
+		{
+			name:           "PlaceholderEncodedPercent",
+			routeFactory:   NewPlaceholderRouteFactory(),
+			routePath:      "/items/:id",
+			requestPath:    "/items/100%25%2Fday",
+			expectedParams: RouteParams{"id": "100%/day"},
+		},
+		{
+			name:        "SplitSegmentsDoNotMatch",
+			routePath:   "/items/:id",
+			requestPath: "/items/a/b",
+			expectedErr: ErrRouteNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			routeFactory := testCase.routeFactory
+			if routeFactory == nil {
+				routeFactory = NewPlaceholderRouteFactory()
+			}
+
+			router := New(routeFactory)
+			router.UseRawPath = true
+			router.EncodedSlashPolicy = testCase.encodedSlashPolicy
+			router.InvalidEscapePolicy = testCase.invalidEscapePolicy
+
+			router.Get(testCase.routePath, HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+				return nil
+			}), "")
+
+			request := httptest.NewRequest(http.MethodGet, testCase.requestPath, nil)
+
+			routeMatch, err := router.Match(request)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expectedParams, routeMatch.Params)
+			}
+		})
+	}
+}
+
+func TestRouter_UnescapeRouteParam(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		value               string
+		encodedSlashPolicy  EncodedSlashPolicy
+		invalidEscapePolicy InvalidEscapePolicy
+		expectedValue       string
+		expectedErr         error
+	}{
+		{name: "NoEscapes", value: "abc", expectedValue: "abc"},
+		{name: "Escapes", value: "a%20b%2Fc", expectedValue: "a b/c"},
+		{name: "KeepEncodedSlash", value: "a%20b%2fc", encodedSlashPolicy: EncodedSlashKeep, expectedValue: "a b%2Fc"},
+		{name: "RejectEncodedSlash", value: "a%2Fb", encodedSlashPolicy: EncodedSlashReject, expectedErr: ErrInvalidPathEscape},
+		{name: "RejectInvalidEscape", value: "100%zz", expectedErr: ErrInvalidPathEscape},
+		{name: "PassThroughInvalidEscape", value: "100%zz", invalidEscapePolicy: InvalidEscapePassThrough, expectedValue: "100%zz"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := New()
+			router.EncodedSlashPolicy = testCase.encodedSlashPolicy
+			router.InvalidEscapePolicy = testCase.invalidEscapePolicy
+
+			value, err := router.unescapeRouteParam(testCase.value)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestRouter_UseRawPath_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	router := New(NewPlaceholderRouteFactory())
+	router.UseRawPath = true
+	router.EncodedSlashPolicy = EncodedSlashReject
+
+	router.Get("/items/:id", HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		return nil
+	}), "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/items/a%2Fb", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Status code mismatch")
+}
+
+func TestRouter_UseRawPath_Disabled(t *testing.T) {
+	t.Parallel()
+
+	router := New(NewPlaceholderRouteFactory())
+	router.Get("/items/:id", HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		return nil
+	}), "")
+
+	request := httptest.NewRequest(http.MethodGet, "/items/a%2Fb", nil)
+
+	_, err := router.Match(request)
+	assert.ErrorIs(t, err, ErrRouteNotFound)
+}
@@ -0,0 +1,78 @@
+package httprouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is returned by the Recover middleware when the next handler panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+	// Route is the route that matched the request, it is empty if the panic happened before a route was matched.
+	Route RouteInfo
+	// RequestID is the ID set by the RequestID middleware, if any.
+	RequestID string
+}
+
+func (e *PanicError) Error() string {
+	message := fmt.Sprintf("httprouter: panic: %v", e.Value)
+	if e.Route.Pattern != "" {
+		message = fmt.Sprintf("httprouter: panic in route %q (%s): %v", e.Route.Name, e.Route.Pattern, e.Value)
+	}
+
+	if e.RequestID != "" {
+		message += " (request ID: " + e.RequestID + ")"
+	}
+
+	return message
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+type RecoverConfig struct {
+	// OnPanic is called with every recovered panic before the error is returned, e.g. to send an alert.
+	OnPanic func(request *http.Request, panicErr *PanicError)
+}
+
+// Recover converts a panic of the next handler into a PanicError that is returned to the router error handler.
+// http.ErrAbortHandler is panicked again, so net/http can abort the response as usual.
+// Add it with Router.Pre to recover from panics in every handler and middleware.
+func Recover(config RecoverConfig) MiddlewareFunc {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) (err error) {
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				if abortErr, ok := value.(error); ok && errors.Is(abortErr, http.ErrAbortHandler) {
+					panic(value)
+				}
+
+				panicErr := &PanicError{
+					Value:     value,
+					Stack:     debug.Stack(),
+					Route:     CurrentRoute(request.Context()),
+					RequestID: RequestIDFromContext(request.Context()),
+				}
+
+				if config.OnPanic != nil {
+					config.OnPanic(request, panicErr)
+				}
+
+				err = panicErr
+			}()
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package httprouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	errPanic := errors.New("boom")
+
+	var onPanicErr, handledErr error
+
+	router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	router.ErrorHandler = func(responseWriter http.ResponseWriter, _ *http.Request, err error) {
+		handledErr = err
+		responseWriter.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	router.Pre(httprouter.Recover(httprouter.RecoverConfig{
+		OnPanic: func(_ *http.Request, panicErr *httprouter.PanicError) {
+			onPanicErr = panicErr
+		},
+	}))
+
+	router.Get("/users/:id", httprouter.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		panic(errPanic)
+	}), "user")
+
+	recorder := httptest.NewRecorder()
+
+	assert.NotPanics(t, func() {
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "Status code mismatch")
+	assert.Same(t, onPanicErr, handledErr)
+	assert.ErrorIs(t, handledErr, errPanic)
+
+	var panicErr *httprouter.PanicError
+	if assert.ErrorAs(t, handledErr, &panicErr) {
+		assert.Equal(t, "/users/:id", panicErr.Route.Pattern)
+		assert.Equal(t, "user", panicErr.Route.Name)
+		assert.NotEmpty(t, panicErr.Stack)
+		assert.Contains(t, panicErr.Error(), `route "user" (/users/:id): boom`)
+	}
+}
+
+func TestRecover_DefaultErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Use(httprouter.Recover(httprouter.RecoverConfig{}))
+	router.Get("/test", httprouter.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		panic("boom")
+	}), "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code, "Status code mismatch")
+}
+
+func TestRecover_ErrAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := httprouter.Recover(httprouter.RecoverConfig{})(httprouter.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) error {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithError(t, http.ErrAbortHandler.Error(), func() {
+		_ = handler.Handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,133 @@
+package httprouter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the length of a request ID read from a request header.
+const maxRequestIDLength = 128
+
+type RequestIDConfig struct {
+	// Header is read and echoed in the response, DefaultRequestIDHeader is used when it is empty.
+	Header string
+	// Generator generates IDs of requests without one, NewUUIDv4 is used when it is nil.
+	Generator func() string
+	// IgnoreIncoming generates an ID for every request, e.g. for requests from untrusted clients.
+	IgnoreIncoming bool
+}
+
+// RequestID reads the request ID from the header or generates a new one, puts it into the request context
+// and echoes it in the response header. An incoming ID longer than 128 characters or with characters other than
+// visible ASCII is replaced. DefaultErrorHandler, AccessLog and Recover include the ID in what they produce.
+// Add it with Router.Pre, so unmatched requests get an ID as well.
+func RequestID(config RequestIDConfig) MiddlewareFunc {
+	if config.Header == "" {
+		config.Header = DefaultRequestIDHeader
+	}
+
+	if config.Generator == nil {
+		config.Generator = NewUUIDv4
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			requestID := request.Header.Get(config.Header)
+			if config.IgnoreIncoming || !validRequestID(requestID) {
+				requestID = config.Generator()
+			}
+
+			responseWriter.Header().Set(config.Header, requestID)
+
+			ctx := context.WithValue(request.Context(), requestIDKey, requestID)
+
+			// the router error handler is called with the request the router received, it finds the ID in the state
+			if state, ok := ctx.Value(routeStateKey).(*routeState); ok {
+				state.requestID = requestID
+			}
+
+			return next.Handle(responseWriter, request.WithContext(ctx)) //nolint:wrapcheck
+		})
+	}
+}
+
+// RequestIDFromContext returns the ID set by the RequestID middleware, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey).(string); ok {
+		return requestID
+	}
+
+	if state, ok := ctx.Value(routeStateKey).(*routeState); ok {
+		return state.requestID
+	}
+
+	return ""
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for idx := 0; idx < len(requestID); idx++ {
+		if requestID[idx] < '!' || requestID[idx] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewUUIDv4 returns a random RFC 9562 version 4 UUID.
+func NewUUIDv4() string {
+	var uuid [16]byte
+
+	_, _ = rand.Read(uuid[:])
+
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	var buf [36]byte
+
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+
+	return string(buf[:])
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID: a 48-bit millisecond timestamp followed by 80 random bits, encoded with Crockford's
+// base32, so IDs sort by the time they were generated.
+func NewULID() string {
+	var ulid [16]byte
+
+	binary.BigEndian.PutUint64(ulid[0:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(ulid[6:])
+
+	high := binary.BigEndian.Uint64(ulid[0:8])
+	low := binary.BigEndian.Uint64(ulid[8:16])
+
+	var buf [26]byte
+
+	for idx := len(buf) - 1; idx >= 0; idx-- {
+		buf[idx] = crockfordAlphabet[low&31]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+
+	return string(buf[:])
+}
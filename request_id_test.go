@@ -0,0 +1,172 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uuidV4Pattern = `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		config            httprouter.RequestIDConfig
+		requestHeaders    map[string]string
+		expectedHeader    string
+		expectedRequestID string
+		expectedPattern   string
+	}{
+		{
+			name:            "generated UUID",
+			expectedHeader:  "X-Request-ID",
+			expectedPattern: uuidV4Pattern,
+		},
+		{
+			name:              "incoming ID",
+			requestHeaders:    map[string]string{"X-Request-ID": "abc-123"},
+			expectedHeader:    "X-Request-ID",
+			expectedRequestID: "abc-123",
+		},
+		{
+			name:            "invalid incoming ID",
+			requestHeaders:  map[string]string{"X-Request-ID": "abc\n123"},
+			expectedHeader:  "X-Request-ID",
+			expectedPattern: uuidV4Pattern,
+		},
+		{
+			name:            "too long incoming ID",
+			requestHeaders:  map[string]string{"X-Request-ID": strings.Repeat("a", 129)},
+			expectedHeader:  "X-Request-ID",
+			expectedPattern: uuidV4Pattern,
+		},
+		{
+			name:            "ignored incoming ID",
+			config:          httprouter.RequestIDConfig{IgnoreIncoming: true},
+			requestHeaders:  map[string]string{"X-Request-ID": "abc-123"},
+			expectedHeader:  "X-Request-ID",
+			expectedPattern: uuidV4Pattern,
+		},
+		{
+			name:            "custom header and ULID generator",
+			config:          httprouter.RequestIDConfig{Header: "X-Correlation-ID", Generator: httprouter.NewULID},
+			requestHeaders:  map[string]string{"X-Request-ID": "abc-123"},
+			expectedHeader:  "X-Correlation-ID",
+			expectedPattern: `^[0-9A-HJKMNP-TV-Z]{26}$`,
+		},
+		{
+			name: "custom generator",
+			config: httprouter.RequestIDConfig{Generator: func() string {
+				return "generated"
+			}},
+			expectedHeader:    "X-Request-ID",
+			expectedRequestID: "generated",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var handlerRequestID string
+
+			router := httprouter.New()
+			router.Pre(httprouter.RequestID(testCase.config))
+			router.Get("/", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				handlerRequestID = httprouter.RequestIDFromContext(request.Context())
+
+				return nil
+			}), "")
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			for headerName, headerValue := range testCase.requestHeaders {
+				request.Header.Set(headerName, headerValue)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			responseRequestID := responseRecorder.Header().Get(testCase.expectedHeader)
+
+			assert.Equal(t, handlerRequestID, responseRequestID)
+
+			if testCase.expectedPattern != "" {
+				assert.Regexp(t, testCase.expectedPattern, responseRequestID)
+			} else {
+				assert.Equal(t, testCase.expectedRequestID, responseRequestID)
+			}
+		})
+	}
+}
+
+func TestRequestID_PickedUp(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logBuf   bytes.Buffer
+		panicErr *httprouter.PanicError
+	)
+
+	router := httprouter.New()
+	router.Pre(
+		httprouter.RequestID(httprouter.RequestIDConfig{}),
+		httprouter.AccessLog(httprouter.AccessLogConfig{Logger: slog.New(slog.NewJSONHandler(&logBuf, nil))}),
+		httprouter.Recover(httprouter.RecoverConfig{
+			OnPanic: func(_ *http.Request, err *httprouter.PanicError) {
+				panicErr = err
+			},
+		}),
+	)
+	router.Get("/panic", httprouter.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	}), "panic")
+	router.Get("/forbidden", &mockHandler{errToReturn: httprouter.NewHTTPError(http.StatusForbidden, nil)}, "")
+
+	testCases := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/forbidden", expectedStatus: http.StatusForbidden, expectedBody: "Forbidden (request ID: id-1)\n"},
+		{path: "/panic", expectedStatus: http.StatusInternalServerError, expectedBody: "Internal Server Error (request ID: id-2)\n"},
+	}
+
+	for idx, testCase := range testCases {
+		request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+		request.Header.Set("X-Request-ID", "id-"+strconv.Itoa(idx+1))
+
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+
+		assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+		assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+		assert.Equal(t, request.Header.Get("X-Request-ID"), responseRecorder.Header().Get("X-Request-ID"))
+	}
+
+	require.NotNil(t, panicErr)
+	assert.Equal(t, "id-2", panicErr.RequestID)
+	assert.Equal(t, `httprouter: panic in route "panic" (/panic): boom (request ID: id-2)`, panicErr.Error())
+
+	lines := bytes.Split(bytes.TrimSpace(logBuf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	for idx, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+
+		assert.Equal(t, "id-"+strconv.Itoa(idx+1), record["request_id"])
+	}
+}
@@ -0,0 +1,64 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequiredScopes is the metadata listing the scopes a route requires, read it with Meta to audit the routes.
+type RequiredScopes []string
+
+// Require returns a router value whose routes require the client to have all the scopes, e.g.
+// httprouter.Require(r, "orders:write").Post("/orders", handler, ""). The scopes are checked against
+// the Identity put in the context by an authentication middleware, which must run before, and a client missing one
+// is answered with 403 Forbidden by the router error handler. Groups created from it inherit the requirement,
+// and the scopes required by nested groups are added to it.
+func Require(r Router, scopes ...string) Router {
+	if len(scopes) == 0 {
+		panic("httprouter: required scopes are empty")
+	}
+
+	router, ok := r.(metaRouter)
+	if !ok {
+		panic(fmt.Sprintf("httprouter: %T does not support metadata", r))
+	}
+
+	inherited, _ := router.metaValue(metaKey[RequiredScopes]{})
+	required, _ := inherited.(RequiredScopes)
+
+	required = slices.Clip(required)
+	for _, name := range scopes {
+		if !slices.Contains(required, name) {
+			required = append(required, name)
+		}
+	}
+
+	scopes = slices.Clone(scopes)
+
+	return router.withMeta(metaKey[RequiredScopes]{}, required).With(requireScopes(scopes))
+}
+
+// requireScopes checks the scopes of one Require call, the inherited ones are checked by the outer middlewares.
+func requireScopes(scopes []string) MiddlewareFunc {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			identity, _ := IdentityFromContext(request.Context())
+
+			var missing []string
+
+			for _, name := range scopes {
+				if !slices.Contains(identity.Scopes, name) {
+					missing = append(missing, name)
+				}
+			}
+
+			if len(missing) > 0 {
+				return NewHTTPError(http.StatusForbidden, fmt.Errorf("%w: %s", ErrForbidden, strings.Join(missing, ", ")))
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
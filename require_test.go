@@ -0,0 +1,109 @@
+package httprouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Use(httprouter.APIKeyAuth(httprouter.APIKeyAuthConfig{
+		Lookup: func(_ context.Context, key string) (httprouter.Identity, bool) {
+			return httprouter.Identity{Subject: key, Scopes: strings.Fields(key)}, true
+		},
+	}))
+
+	handler := &mockHandler{}
+
+	router.Get("/health", handler, "health")
+
+	httprouter.Require(router, "orders:read").Route("/orders", func(orders httprouter.Router) {
+		orders.Get("/", handler, "list-orders")
+		httprouter.Require(orders, "orders:write", "orders:read").Post("/", handler, "create-order")
+	})
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		scopes         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "route without requirements",
+			method:         http.MethodGet,
+			path:           "/health",
+			scopes:         "none",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "group requirement met",
+			method:         http.MethodGet,
+			path:           "/orders/",
+			scopes:         "orders:read",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "group requirement missing",
+			method:         http.MethodGet,
+			path:           "/orders/",
+			scopes:         "orders:write",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden\n",
+		},
+		{
+			name:           "route and group requirements met",
+			method:         http.MethodPost,
+			path:           "/orders/",
+			scopes:         "orders:read orders:write",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "route requirement missing",
+			method:         http.MethodPost,
+			path:           "/orders/",
+			scopes:         "orders:read",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			request.Header.Set("X-API-Key", testCase.scopes)
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+		})
+	}
+
+	required := map[string]httprouter.RequiredScopes{}
+
+	for _, routeInfo := range router.Routes() {
+		if scopes, ok := httprouter.Meta[httprouter.RequiredScopes](routeInfo); ok {
+			required[routeInfo.Name] = scopes
+		}
+	}
+
+	assert.Equal(t, map[string]httprouter.RequiredScopes{
+		"list-orders":  {"orders:read"},
+		"create-order": {"orders:read", "orders:write"},
+	}, required)
+}
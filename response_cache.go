@@ -0,0 +1,229 @@
+package httprouter
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ResponseCacheConfig struct {
+	// MaxEntries is the number of responses kept, the least recently used response is evicted to make room
+	// for a new one. 1000 is used when it is zero.
+	MaxEntries int
+	// Now returns the current time, time.Now is used when it is nil.
+	Now func() time.Time
+}
+
+// ResponseCache keeps the responses cached by the Cache middleware in memory. Responses are kept until they can't
+// be served even stale, or until they are the least recently used once there are more than MaxEntries.
+type ResponseCache struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	variants map[string]*cacheVariants
+}
+
+type cachedResponse struct {
+	key          string
+	primaryKey   string
+	route        string
+	status       int
+	header       http.Header
+	body         []byte
+	storedAt     time.Time
+	ttl          time.Duration
+	staleTTL     time.Duration
+	revalidating bool
+	// shared responses are marked public, must-revalidate or s-maxage and may be served to authorized requests.
+	shared bool
+}
+
+// cacheVariants holds the request headers the responses stored under a primary key vary on.
+type cacheVariants struct {
+	vary  []string
+	count int
+}
+
+func NewResponseCache(config ResponseCacheConfig) *ResponseCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &ResponseCache{
+		maxEntries: config.MaxEntries,
+		now:        config.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		variants:   make(map[string]*cacheVariants),
+	}
+}
+
+// InvalidateRoute removes the cached responses of the route with the name, or with the pattern for unnamed routes.
+func (c *ResponseCache) InvalidateRoute(routeName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+
+		if element.Value.(*cachedResponse).route == routeName {
+			c.remove(element)
+		}
+
+		element = next
+	}
+}
+
+// Purge removes all the cached responses.
+func (c *ResponseCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.variants = make(map[string]*cacheVariants)
+}
+
+// Len returns the number of cached responses.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// get returns the response cached for the request under the primary key, unless it is too old to be served even stale.
+func (c *ResponseCache) get(primaryKey string, request *http.Request, now time.Time) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	variants, ok := c.variants[primaryKey]
+	if !ok {
+		return nil, false
+	}
+
+	element, ok := c.entries[variantKey(primaryKey, variants.vary, request)]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cachedResponse)
+	if now.Sub(entry.storedAt) > entry.ttl+entry.staleTTL {
+		c.remove(element)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+
+	return entry, true
+}
+
+func (c *ResponseCache) set(entry *cachedResponse, vary []string, request *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	variants, ok := c.variants[entry.primaryKey]
+	if ok && !slicesEqualFold(variants.vary, vary) {
+		// the variants stored so far were selected with other headers
+		for element := c.lru.Front(); element != nil; {
+			next := element.Next()
+
+			if element.Value.(*cachedResponse).primaryKey == entry.primaryKey {
+				c.remove(element)
+			}
+
+			element = next
+		}
+	}
+
+	entry.key = variantKey(entry.primaryKey, vary, request)
+
+	if element, ok := c.entries[entry.key]; ok {
+		c.remove(element)
+	}
+
+	variants, ok = c.variants[entry.primaryKey]
+	if !ok {
+		variants = &cacheVariants{vary: vary}
+		c.variants[entry.primaryKey] = variants
+	}
+
+	variants.count++
+	c.entries[entry.key] = c.lru.PushFront(entry)
+
+	if c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// startRevalidation tells whether the caller should refresh the stale response, only one caller does at a time.
+func (c *ResponseCache) startRevalidation(entry *cachedResponse) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.revalidating {
+		return false
+	}
+
+	entry.revalidating = true
+
+	return true
+}
+
+func (c *ResponseCache) finishRevalidation(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.revalidating = false
+}
+
+func (c *ResponseCache) remove(element *list.Element) {
+	entry := element.Value.(*cachedResponse)
+
+	c.lru.Remove(element)
+	delete(c.entries, entry.key)
+
+	if variants, ok := c.variants[entry.primaryKey]; ok {
+		variants.count--
+		if variants.count <= 0 {
+			delete(c.variants, entry.primaryKey)
+		}
+	}
+}
+
+func variantKey(primaryKey string, vary []string, request *http.Request) string {
+	var key strings.Builder
+
+	key.WriteString(primaryKey)
+
+	for _, name := range vary {
+		key.WriteString("\x00")
+		key.WriteString(strings.Join(request.Header.Values(name), ","))
+	}
+
+	return key.String()
+}
+
+func slicesEqualFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
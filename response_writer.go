@@ -0,0 +1,62 @@
+package httprouter
+
+import (
+	"net/http"
+)
+
+// responseRecorder records the status code and the number of bytes written to the wrapped ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func newResponseRecorder(responseWriter http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{
+		ResponseWriter: responseWriter,
+		status:         http.StatusOK,
+	}
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.status = statusCode
+		w.wroteHeader = statusCode >= http.StatusOK || statusCode == http.StatusSwitchingProtocols
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
+
+func (w *responseRecorder) Flush() {
+	w.wroteHeader = true
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusFor returns the status code of the response, if nothing was written yet and the handler returned an error,
+// it is the status code the error is going to be answered with by DefaultErrorHandler.
+func (w *responseRecorder) statusFor(err error) int {
+	if err != nil && !w.wroteHeader {
+		return ErrorStatusCode(err)
+	}
+
+	return w.status
+}
@@ -22,15 +22,52 @@ type ctxKey int
 const (
 	routeParamsKey ctxKey = iota
 	routeNameKey
+	originalPathKey
+	handlerErrorKey
+	routeStateKey
+	mountPatternKey
+	spanKey
+	routerKey
+	requestIDKey
+	originalBodyKey
+	identityKey
+	csrfKey
+	clientIPKey
+	etagKey
 )
 
 func RouteParam(ctx context.Context, param string) string {
+	return routeParamsFrom(ctx)[param]
+}
+
+func routeParamsFrom(ctx context.Context) RouteParams {
 	routeParams, ok := ctx.Value(routeParamsKey).(RouteParams)
 	if !ok {
-		return ""
+		return nil
+	}
+
+	return routeParams
+}
+
+// mergeRouteParams adds params of a mounted router to the params matched by the parent router,
+// a param of the mounted router wins over a parent param with the same name.
+func mergeRouteParams(ctx context.Context, params RouteParams) RouteParams {
+	parentParams := routeParamsFrom(ctx)
+	if len(parentParams) == 0 {
+		return params
+	}
+
+	mergedParams := make(RouteParams, len(parentParams)+len(params))
+
+	for paramName, paramValue := range parentParams {
+		mergedParams[paramName] = paramValue
+	}
+
+	for paramName, paramValue := range params {
+		mergedParams[paramName] = paramValue
 	}
 
-	return routeParams[param]
+	return mergedParams
 }
 
 func RouteName(ctx context.Context) string {
@@ -42,6 +79,17 @@ func RouteName(ctx context.Context) string {
 	return routeName
 }
 
+// OriginalPath returns the request path before a mount prefix was stripped from it,
+// or an empty string if the request was not passed to a mounted handler.
+func OriginalPath(ctx context.Context) string {
+	originalPath, ok := ctx.Value(originalPathKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return originalPath
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -0,0 +1,121 @@
+package httprouter
+
+import (
+	"context"
+	"strings"
+)
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Name    string
+	Pattern string
+	// Methods is empty for routes that match any method, e.g. mounted handlers.
+	Methods []string
+	// Factory is the name of the route factory that created the route, or "mount" for mounted handlers.
+	Factory string
+
+	meta map[any]any
+}
+
+// routeState is put into the request context before Pre middlewares run and filled once a route is matched,
+// so the matched route is known to Pre middlewares after the next handler returns.
+type routeState struct {
+	routeInfo RouteInfo
+	params    RouteParams
+	requestID string
+	clientIP  string
+	// authenticated is set once an auth middleware identified the client, even in a context derived later.
+	authenticated bool
+}
+
+// isolateRouteState returns a context with a copy of the route state for a handler that runs in another goroutine,
+// so the handler and the middlewares of the request don't race on the state. The returned function copies the state
+// of the handler back, call it only once the handler returned.
+func isolateRouteState(ctx context.Context) (context.Context, func()) {
+	state, ok := ctx.Value(routeStateKey).(*routeState)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	isolated := *state
+
+	return context.WithValue(ctx, routeStateKey, &isolated), func() {
+		*state = isolated
+	}
+}
+
+// CurrentRoute returns the route that matched the request, or an empty RouteInfo if no route matched (yet).
+// For a route of a mounted router the pattern includes the mount prefix.
+func CurrentRoute(ctx context.Context) RouteInfo {
+	state, ok := ctx.Value(routeStateKey).(*routeState)
+	if !ok {
+		return RouteInfo{}
+	}
+
+	return state.routeInfo
+}
+
+const mountFactoryName = "mount"
+
+// routesLister is implemented by routers that can list their routes, it is used to show routes of a mounted router.
+type routesLister interface {
+	Routes() []RouteInfo
+}
+
+// Routes returns the registered routes in the order they are matched,
+// a mounted router is expanded into its own routes prefixed with the mount prefix.
+func (r *router) Routes() []RouteInfo {
+	routeInfos := make([]RouteInfo, 0, len(r.routeInfos))
+
+	for idx, routeInfo := range r.routeInfos {
+		mountRoute, ok := r.routes[idx].(*MountRoute)
+		if !ok {
+			routeInfos = append(routeInfos, routeInfo)
+
+			continue
+		}
+
+		mountedRouter, ok := r.mounts[mountRoute].(routesLister)
+		if !ok {
+			routeInfos = append(routeInfos, routeInfo)
+
+			continue
+		}
+
+		mountPrefix := strings.Trim(mountRoute.Prefix, "/")
+
+		for _, mountedRouteInfo := range mountedRouter.Routes() {
+			if mountPrefix != "" {
+				mountedRouteInfo.Pattern = prefixPath(mountPrefix, mountedRouteInfo.Pattern)
+			}
+
+			routeInfos = append(routeInfos, mountedRouteInfo)
+		}
+	}
+
+	return routeInfos
+}
+
+// routeMethods returns the methods the route matches, a route may override the methods it was registered with.
+func routeMethods(route Route, methods []string) []string {
+	if serveMuxRoute, ok := route.(*ServeMuxRoute); ok {
+		return serveMuxRoute.Methods
+	}
+
+	return methods
+}
+
+// matchedRouteParams returns params of the route that matched the request, unlike RouteParam
+// it works in Pre middlewares after the next handler returns.
+func matchedRouteParams(ctx context.Context) RouteParams {
+	if params := routeParamsFrom(ctx); params != nil {
+		return params
+	}
+
+	state, ok := ctx.Value(routeStateKey).(*routeState)
+	if !ok {
+		return nil
+	}
+
+	return state.params
+}
@@ -0,0 +1,70 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Routes(t *testing.T) {
+	t.Parallel()
+
+	usersRouter := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	usersRouter.Get("/users/:uid", &mockHandler{}, "user")
+
+	router := httprouter.New(httprouter.NewRegexRouteFactory())
+	router.Get("/health", &mockHandler{}, "")
+	router.Any(`/items/{id:\d+}`, []string{http.MethodPut, http.MethodPatch}, &mockHandler{}, "item")
+	router.Mount("/tenants/:tid", usersRouter)
+	router.Mount("/debug/pprof/", http.NotFoundHandler())
+
+	expected := []httprouter.RouteInfo{
+		{Name: "/health", Pattern: "/health", Methods: []string{http.MethodGet}, Factory: "literal"},
+		{Name: "item", Pattern: `/items/{id:\d+}`, Methods: []string{http.MethodPut, http.MethodPatch}, Factory: "regex"},
+		{Name: "user", Pattern: "/tenants/:tid/users/:uid", Methods: []string{http.MethodGet}, Factory: "placeholder"},
+		{Name: "/debug/pprof/", Pattern: "/debug/pprof/*", Factory: "mount"},
+	}
+
+	assert.Equal(t, expected, router.Routes())
+}
+
+func TestCurrentRoute(t *testing.T) {
+	t.Parallel()
+
+	var handlerRoute, preRouteBefore, preRouteAfter httprouter.RouteInfo
+
+	usersRouter := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+	usersRouter.Get("/users/:uid", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+		handlerRoute = httprouter.CurrentRoute(request.Context())
+
+		return nil
+	}), "user")
+
+	router := httprouter.New()
+	router.Mount("/tenants/:tid", usersRouter)
+	router.Pre(func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			preRouteBefore = httprouter.CurrentRoute(request.Context())
+			err := next.Handle(responseWriter, request)
+			preRouteAfter = httprouter.CurrentRoute(request.Context())
+
+			return err //nolint:wrapcheck
+		})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tenants/1/users/2", nil))
+
+	expected := httprouter.RouteInfo{
+		Name:    "user",
+		Pattern: "/tenants/:tid/users/:uid",
+		Methods: []string{http.MethodGet},
+		Factory: "placeholder",
+	}
+
+	assert.Equal(t, httprouter.RouteInfo{}, preRouteBefore)
+	assert.Equal(t, expected, handlerRoute)
+	assert.Equal(t, expected, preRouteAfter)
+}
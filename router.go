@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 )
 
 type Router interface {
+	http.Handler
+
 	Match(request *http.Request) (RouteMatch, error)
+	Routes() []RouteInfo
 
 	Get(path string, handler Handler, routeName string)
 	Post(path string, handler Handler, routeName string)
@@ -19,27 +23,54 @@ type Router interface {
 	Connect(path string, handler Handler, routeName string)
 	Trace(path string, handler Handler, routeName string)
 	Any(path string, methods []string, handler Handler, routeName string)
-
-	Group(callback func(r Router))
+	HandleHTTP(path string, methods []string, handler http.Handler, routeName string)
+	Mount(prefix string, handler http.Handler)
+	NotFound(handler Handler)
+	MethodNotAllowed(handler Handler)
+
+	Group(callback func(r Router)) Router
+	Route(prefix string, callback func(r Router)) Router
+	With(middlewares ...MiddlewareFunc) Router
 	Use(middlewares ...MiddlewareFunc)
 	WithPrefix(prefix string)
 }
 
 type router struct {
+	scope
+
 	routes            []Route
+	routeInfos        []RouteInfo
+	routeMiddlewares  []MiddlewareFunc
+	mounts            map[Route]http.Handler
 	routeFactoriesSet map[string]struct{}
 	routeFactories    []RouteFactory
-	middleware        MiddlewareFunc
-	prefix            string
+	fallbacks         []*fallback
+	preMiddleware     MiddlewareFunc
+	postMiddleware    MiddlewareFunc
+	handler           Handler
 
 	NotFoundHandler Handler
+	// ErrorHandler answers requests whose handler returned an error, DefaultErrorHandler is used when it is nil.
+	ErrorHandler ErrorHandlerFunc
+
+	// UseRawPath makes routes match against request.URL.EscapedPath() instead of the decoded request.URL.Path,
+	// so an encoded slash in a param does not split it into two segments. Other escape sequences are decoded before
+	// matching, so routes registered with decoded text match either way. Captured params are unescaped afterwards
+	// according to EncodedSlashPolicy and InvalidEscapePolicy.
+	UseRawPath          bool
+	EncodedSlashPolicy  EncodedSlashPolicy
+	InvalidEscapePolicy InvalidEscapePolicy
 }
 
 func New(routeFactories ...RouteFactory) *router { //nolint:golint,revive
 	router := &router{
 		routeFactoriesSet: make(map[string]struct{}),
+		mounts:            make(map[Route]http.Handler),
 	}
 
+	router.scope.router = router
+	router.handler = HandlerFunc(router.dispatch)
+
 	for _, routeFactory := range routeFactories {
 		router.registerRouteFactory(routeFactory)
 	}
@@ -59,113 +90,39 @@ func (r *router) registerRouteFactory(routeFactory RouteFactory) {
 	r.routeFactories = append(r.routeFactories, routeFactory)
 }
 
-func (r *router) route(path string, methods []string, handler Handler, routeName string) {
-	if r.middleware != nil {
-		handler = r.middleware(handler)
-	}
-
-	if r.prefix != "" {
-		path = "/" + r.prefix + path
-	}
-
-	for _, routeFactory := range r.routeFactories {
-		if routeFactory.Handles(path) {
-			route := routeFactory.CreateRoute(path, methods, handler, routeName)
-			r.routes = append(r.routes, route)
-
-			return
-		}
-	}
-}
-
-func (r *router) Get(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodGet}, handler, routeName)
-}
-
-func (r *router) Post(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodPost}, handler, routeName)
-}
-
-func (r *router) Put(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodPut}, handler, routeName)
-}
-
-func (r *router) Patch(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodPatch}, handler, routeName)
-}
-
-func (r *router) Delete(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodDelete}, handler, routeName)
+// addRoute adds the route, the scope middleware the route handler is wrapped with is kept to pass preflights
+// of the route to a CORS middleware of the scope as well.
+func (r *router) addRoute(route Route, routeInfo RouteInfo, middleware MiddlewareFunc) {
+	r.routes = append(r.routes, route)
+	r.routeInfos = append(r.routeInfos, routeInfo)
+	r.routeMiddlewares = append(r.routeMiddlewares, middleware)
 }
 
-func (r *router) Options(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodOptions}, handler, routeName)
-}
-
-func (r *router) Head(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodHead}, handler, routeName)
-}
-
-func (r *router) Connect(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodConnect}, handler, routeName)
-}
-
-func (r *router) Trace(path string, handler Handler, routeName string) {
-	r.route(path, []string{http.MethodTrace}, handler, routeName)
-}
-
-func (r *router) Any(path string, methods []string, handler Handler, routeName string) {
-	r.route(path, methods, handler, routeName)
-}
-
-func (r *router) Group(callback func(r Router)) {
-	routerMiddleware := r.middleware
-	routerPrefix := r.prefix
-
-	callback(r)
-
-	// remove group middleware and prefix
-	r.middleware = routerMiddleware
-	r.prefix = routerPrefix
-}
-
-// Use
-// r.Use(middleware1)
-// r.Use(middleware2)
-// Or r.Use(middleware1, middleware2)
-// -> middleware1(middleware2(next)).
-func (r *router) Use(middlewares ...MiddlewareFunc) {
-	for _, middleware := range middlewares {
-		middleware := middleware // shadow for closure
-
-		if r.middleware != nil {
-			routerMiddleware := r.middleware
-			r.middleware = func(next Handler) Handler {
-				return routerMiddleware(middleware(next))
-			}
-
-			continue
-		}
+func (r *router) Match(request *http.Request) (RouteMatch, error) { //nolint:ireturn
+	routeMatch, _, err := r.match(request)
 
-		r.middleware = middleware
-	}
+	return routeMatch, err
 }
 
-func (r *router) WithPrefix(prefix string) {
-	if r.prefix != "" {
-		r.prefix += "/" + prefix
-
-		return
+func (r *router) match(request *http.Request) (RouteMatch, RouteInfo, error) {
+	idx, routeMatch, err := r.matchRoute(request)
+	if err != nil {
+		return routeMatch, RouteInfo{}, err
 	}
 
-	r.prefix = prefix
+	return routeMatch, r.routeInfos[idx], nil
 }
 
-func (r *router) Match(request *http.Request) (RouteMatch, error) { //nolint:ireturn
+// matchRoute returns the index of the matched route along with the match.
+func (r *router) matchRoute(request *http.Request) (int, RouteMatch, error) {
 	var routeMatch RouteMatch
 	var methodNotAllowed bool
 
-	for _, route := range r.routes {
+	if r.UseRawPath {
+		request = rawPathRequest(request)
+	}
+
+	for idx, route := range r.routes {
 		routeMatch, err := route.Match(request)
 		if err != nil {
 			switch {
@@ -177,45 +134,136 @@ func (r *router) Match(request *http.Request) (RouteMatch, error) { //nolint:ire
 				continue
 			}
 
-			return routeMatch, err //nolint:wrapcheck
+			return idx, routeMatch, err //nolint:wrapcheck
 		}
 
-		return routeMatch, nil
+		if r.UseRawPath {
+			routeMatch.Params, err = r.unescapeRouteParams(routeMatch.Params)
+			if err != nil {
+				return idx, RouteMatch{}, err
+			}
+		}
+
+		return idx, routeMatch, nil
 	}
 
 	if methodNotAllowed {
-		return routeMatch, ErrMethodNotAllowed
+		return -1, routeMatch, ErrMethodNotAllowed
 	}
 
-	return routeMatch, ErrRouteNotFound
+	return -1, routeMatch, ErrRouteNotFound
+}
+
+// Pre adds middlewares that wrap the whole request handling, including NotFound and MethodNotAllowed responses.
+// They run before the route is matched, so they may rewrite the request, and apply to every route no matter
+// when it was registered.
+//
+// Middlewares run in the following order:
+//  1. Pre middlewares, in the order they were added;
+//  2. route matching;
+//  3. PostMatch middlewares, in the order they were added, for matched routes only;
+//  4. Use middlewares of the route scope, in the order they were added, or the scope middlewares of a fallback handler;
+//  5. the handler.
+func (r *router) Pre(middlewares ...MiddlewareFunc) {
+	r.preMiddleware = chainMiddleware(r.preMiddleware, middlewares...)
+	r.handler = r.preMiddleware(HandlerFunc(r.dispatch))
+}
+
+// PostMatch adds middlewares that run after a route is matched, route params and name are already in the request
+// context. Unlike Use they apply to every route no matter when it was registered, see Pre for the order.
+func (r *router) PostMatch(middlewares ...MiddlewareFunc) {
+	r.postMiddleware = chainMiddleware(r.postMiddleware, middlewares...)
 }
 
 func (r *router) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
-	routeMatch, err := r.Match(request)
+	ctx := context.WithValue(request.Context(), routerKey, r)
+	if _, ok := ctx.Value(routeStateKey).(*routeState); !ok {
+		ctx = context.WithValue(ctx, routeStateKey, &routeState{})
+	}
+
+	request = request.WithContext(ctx)
+
+	err := r.handler.Handle(responseWriter, request)
+	if err != nil {
+		r.handleError(responseWriter, request, err)
+	}
+}
+
+func (r *router) methodNotAllowed(responseWriter http.ResponseWriter, request *http.Request) error {
+	if handler, request := r.fallbackHandler(request, methodNotAllowedHandlerOf); handler != nil {
+		return handler.Handle(responseWriter, request) //nolint:wrapcheck
+	}
+
+	http.Error(responseWriter, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+	return nil
+}
+
+func (r *router) dispatch(responseWriter http.ResponseWriter, request *http.Request) error {
+	routeMatch, routeInfo, err := r.match(request)
+	if errors.Is(err, ErrMethodNotAllowed) && isPreflightRequest(request) {
+		routeMatch, routeInfo, err = r.matchPreflight(request)
+	}
+
 	if err != nil {
 		switch {
+		case errors.Is(err, ErrInvalidPathEscape):
+			return NewHTTPError(http.StatusBadRequest, err)
 		case errors.Is(err, ErrMethodNotAllowed):
-			http.Error(responseWriter, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return r.methodNotAllowed(responseWriter, request)
 		case errors.Is(err, ErrRouteNotFound):
-			if r.NotFoundHandler != nil {
-				err = r.NotFoundHandler.Handle(responseWriter, request)
-				if err != nil {
-					http.Error(responseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-				}
+			handler, request := r.fallbackHandler(request, notFoundHandlerOf)
+			if handler == nil {
+				handler = r.NotFoundHandler
+			}
 
-				return
+			if handler != nil {
+				return handler.Handle(responseWriter, request) //nolint:wrapcheck
 			}
+
 			http.NotFound(responseWriter, request)
+		default:
+			return err
 		}
 
-		return
+		return nil
+	}
+
+	if mountPattern, ok := request.Context().Value(mountPatternKey).(string); ok && mountPattern != "" {
+		routeInfo.Pattern = prefixPath(mountPattern, routeInfo.Pattern)
 	}
 
-	ctx := context.WithValue(request.Context(), routeParamsKey, routeMatch.Params)
+	params := mergeRouteParams(request.Context(), routeMatch.Params)
+
+	if state, ok := request.Context().Value(routeStateKey).(*routeState); ok {
+		state.routeInfo = routeInfo
+		state.params = params
+	}
+
+	ctx := context.WithValue(request.Context(), routeParamsKey, params)
 	ctx = context.WithValue(ctx, routeNameKey, routeMatch.RouteName)
 
-	err = routeMatch.Handler.Handle(responseWriter, request.WithContext(ctx))
-	if err != nil {
-		http.Error(responseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	request = request.WithContext(ctx)
+
+	for paramName, paramValue := range routeParamsFrom(ctx) {
+		request.SetPathValue(paramName, paramValue)
+	}
+
+	handler := routeMatch.Handler
+	if r.postMiddleware != nil {
+		handler = r.postMiddleware(handler)
 	}
+
+	return handler.Handle(responseWriter, request) //nolint:wrapcheck
+}
+
+// prefixPath inserts the prefix in front of the path part of a route path,
+// so a method or host in front of it (e.g. "GET /items") is preserved.
+func prefixPath(prefix string, path string) string {
+	slashIdx := strings.Index(path, "/")
+	if slashIdx <= 0 {
+		return "/" + prefix + path
+	}
+
+	return path[:slashIdx] + "/" + prefix + path[slashIdx:]
 }
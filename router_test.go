@@ -549,3 +549,123 @@ func BenchmarkServeHTTPWithPlaceholderParams(b *testing.B) {
 		router.ServeHTTP(recorder, req)
 	}
 }
+
+func TestRouter_ServeHTTP_PathValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		routeFactory httprouter.RouteFactory
+		path         string
+	}{
+		{"Placeholder", httprouter.NewPlaceholderRouteFactory(), "/items/:id"},
+		{"Regex", httprouter.NewRegexRouteFactory(), `/items/{id:\d+}`},
+		{"ServeMux", httprouter.NewServeMuxRouteFactory(), "GET /items/{id}"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New(testCase.routeFactory)
+
+			var pathValue, routeParam string
+
+			router.Get(testCase.path, httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				pathValue = request.PathValue("id")
+				routeParam = httprouter.RouteParam(request.Context(), "id")
+
+				return nil
+			}), "")
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/items/42", nil)
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+			assert.Equal(t, "42", pathValue)
+			assert.Equal(t, "42", routeParam)
+		})
+	}
+}
+
+func TestRouter_Pre(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Get("/v2/users", &mockHandler{}, "")
+
+	// added after the routes on purpose, Pre middlewares apply to every request
+	router.Pre(headerMiddleware("pre1"), headerMiddleware("pre2"))
+	router.Pre(func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			if request.URL.Path == "/v1/users" {
+				request.URL.Path = "/v2/users"
+			}
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	})
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"Rewrite", http.MethodGet, "/v1/users", http.StatusOK},
+		{"NotFound", http.MethodGet, "/unknown", http.StatusNotFound},
+		{"MethodNotAllowed", http.MethodPost, "/v2/users", http.StatusMethodNotAllowed},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(testCase.method, testCase.path, nil))
+
+			assert.Equal(t, testCase.expectedStatus, recorder.Code, "Status code mismatch")
+			assert.Equal(t, []string{"pre1", "pre2"}, recorder.Header().Values("X-Middleware"))
+		})
+	}
+}
+
+func TestRouter_PostMatch(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+
+	router.Use(headerMiddleware("use"))
+	router.Get("/test", &mockHandler{}, "test")
+
+	var routeName string
+
+	router.PostMatch(func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			routeName = httprouter.RouteName(request.Context())
+			responseWriter.Header().Add("X-Middleware", "post-match")
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	})
+	router.Pre(headerMiddleware("pre"))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+	assert.Equal(t, "test", routeName)
+	assert.Equal(t, []string{"pre", "post-match", "use"}, recorder.Header().Values("X-Middleware"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "Status code mismatch")
+	assert.Equal(t, []string{"pre"}, recorder.Header().Values("X-Middleware"))
+}
@@ -0,0 +1,197 @@
+package httprouter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// scope is a lightweight router value returned by Group, Route and With. It shares the route table of the router
+// it was created from, but carries its own prefix, middleware and metadata, so changing a scope never affects its parent.
+type scope struct {
+	router     *router
+	prefix     string
+	middleware MiddlewareFunc
+	meta       map[any]any
+}
+
+func (s *scope) route(path string, methods []string, handler Handler, routeName string) {
+	if s.middleware != nil {
+		handler = s.middleware(handler)
+	}
+
+	if s.prefix != "" {
+		path = prefixPath(s.prefix, path)
+	}
+
+	for _, routeFactory := range s.router.routeFactories {
+		if routeFactory.Handles(path) {
+			route := routeFactory.CreateRoute(path, methods, handler, routeName)
+
+			if routeName == "" {
+				routeName = path
+			}
+
+			s.router.addRoute(route, RouteInfo{
+				Name:    routeName,
+				Pattern: path,
+				Methods: routeMethods(route, methods),
+				Factory: routeFactory.Name(),
+				meta:    s.meta,
+			}, s.middleware)
+
+			return
+		}
+	}
+}
+
+func (s *scope) Get(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodGet}, handler, routeName)
+}
+
+func (s *scope) Post(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodPost}, handler, routeName)
+}
+
+func (s *scope) Put(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodPut}, handler, routeName)
+}
+
+func (s *scope) Patch(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodPatch}, handler, routeName)
+}
+
+func (s *scope) Delete(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodDelete}, handler, routeName)
+}
+
+func (s *scope) Options(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodOptions}, handler, routeName)
+}
+
+func (s *scope) Head(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodHead}, handler, routeName)
+}
+
+func (s *scope) Connect(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodConnect}, handler, routeName)
+}
+
+func (s *scope) Trace(path string, handler Handler, routeName string) {
+	s.route(path, []string{http.MethodTrace}, handler, routeName)
+}
+
+func (s *scope) Any(path string, methods []string, handler Handler, routeName string) {
+	s.route(path, methods, handler, routeName)
+}
+
+func (s *scope) HandleHTTP(path string, methods []string, handler http.Handler, routeName string) {
+	s.route(path, methods, FromHTTPHandler(handler), routeName)
+}
+
+// Mount serves every request under the prefix with the handler, the prefix is stripped from the request path
+// and the original path is available with OriginalPath. The prefix may contain :name or {name} params,
+// they are passed to a mounted router together with its own route params.
+func (s *scope) Mount(prefix string, handler http.Handler) {
+	if s.prefix != "" {
+		prefix = prefixPath(s.prefix, prefix)
+	}
+
+	var routeHandler Handler = &mountHandler{
+		router:         s.router,
+		handler:        handler,
+		prefix:         prefix,
+		prefixSegments: len(pathSegments(prefix)),
+	}
+
+	if s.middleware != nil {
+		routeHandler = s.middleware(routeHandler)
+	}
+
+	route := &MountRoute{
+		Prefix:  prefix,
+		Handler: routeHandler,
+		Name:    prefix,
+	}
+
+	s.router.addRoute(route, RouteInfo{
+		Name:    prefix,
+		Pattern: strings.TrimSuffix(prefix, "/") + "/*",
+		Factory: mountFactoryName,
+		meta:    s.meta,
+	}, s.middleware)
+
+	s.router.mounts[route] = handler
+}
+
+// Group passes a new scope to the callback and returns it, the scope starts with the prefix and middleware
+// of its parent.
+func (s *scope) Group(callback func(r Router)) Router {
+	group := s.child()
+
+	callback(group)
+
+	return group
+}
+
+// Route is like Group, but the scope passed to the callback has the prefix added.
+func (s *scope) Route(prefix string, callback func(r Router)) Router {
+	group := s.child()
+	group.WithPrefix(prefix)
+
+	callback(group)
+
+	return group
+}
+
+// With returns a new scope with the middlewares added, e.g. r.With(auth).Get("/profile", handler, "").
+func (s *scope) With(middlewares ...MiddlewareFunc) Router {
+	group := s.child()
+	group.Use(middlewares...)
+
+	return group
+}
+
+func (s *scope) child() *scope {
+	return &scope{
+		router:     s.router,
+		prefix:     s.prefix,
+		middleware: s.middleware,
+		meta:       s.meta,
+	}
+}
+
+// Use
+// r.Use(middleware1)
+// r.Use(middleware2)
+// Or r.Use(middleware1, middleware2)
+// -> middleware1(middleware2(next)).
+func (s *scope) Use(middlewares ...MiddlewareFunc) {
+	s.middleware = chainMiddleware(s.middleware, middlewares...)
+}
+
+func (s *scope) WithPrefix(prefix string) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return
+	}
+
+	if s.prefix != "" {
+		s.prefix += "/" + prefix
+
+		return
+	}
+
+	s.prefix = prefix
+}
+
+func (s *scope) Match(request *http.Request) (RouteMatch, error) {
+	return s.router.Match(request)
+}
+
+func (s *scope) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	s.router.ServeHTTP(responseWriter, request)
+}
+
+func (s *scope) Routes() []RouteInfo {
+	return s.router.Routes()
+}
@@ -0,0 +1,118 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func headerMiddleware(value string) httprouter.MiddlewareFunc {
+	return func(next httprouter.Handler) httprouter.Handler {
+		return httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			responseWriter.Header().Add("X-Middleware", value)
+
+			return next.Handle(responseWriter, request) //nolint:wrapcheck
+		})
+	}
+}
+
+func TestScope_GroupIsReusable(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+
+	api := router.Group(func(group httprouter.Router) {
+		group.WithPrefix("api")
+		group.Use(headerMiddleware("api"))
+	})
+
+	api.Get("/users", &mockHandler{}, "")
+	router.Get("/users", &mockHandler{}, "")
+
+	testCases := []struct {
+		path               string
+		expectedMiddleware []string
+	}{
+		{"/api/users", []string{"api"}},
+		{"/users", nil},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.path, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+			assert.Equal(t, testCase.expectedMiddleware, recorder.Header().Values("X-Middleware"))
+		})
+	}
+}
+
+func TestScope_GroupPanicDoesNotLeak(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+
+	assert.Panics(t, func() {
+		router.Group(func(group httprouter.Router) {
+			group.WithPrefix("broken")
+			group.Use(headerMiddleware("broken"))
+
+			panic("group callback failed")
+		})
+	})
+
+	router.Get("/test", &mockHandler{}, "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+	assert.Empty(t, recorder.Header().Values("X-Middleware"))
+}
+
+func TestScope_RouteAndWith(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New()
+	router.Use(headerMiddleware("root"))
+
+	router.Route("/api", func(api httprouter.Router) {
+		api.Use(headerMiddleware("api"))
+
+		api.Route("v1", func(v1 httprouter.Router) {
+			v1.Get("/users", &mockHandler{}, "")
+			v1.With(headerMiddleware("admin")).Delete("/users", &mockHandler{}, "")
+		})
+	})
+
+	testCases := []struct {
+		method             string
+		path               string
+		expectedMiddleware []string
+	}{
+		{http.MethodGet, "/api/v1/users", []string{"root", "api"}},
+		{http.MethodDelete, "/api/v1/users", []string{"root", "api", "admin"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.method, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(testCase.method, testCase.path, nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code, "Status code mismatch")
+			assert.Equal(t, testCase.expectedMiddleware, recorder.Header().Values("X-Middleware"))
+		})
+	}
+}
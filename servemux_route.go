@@ -0,0 +1,226 @@
+package httprouter
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type serveMuxSegmentKind int
+
+const (
+	serveMuxLiteral serveMuxSegmentKind = iota
+	serveMuxWildcard
+	serveMuxMultiWildcard
+)
+
+type serveMuxSegment struct {
+	kind  serveMuxSegmentKind
+	value string // literal value or wildcard name
+}
+
+type ServeMuxRoute struct {
+	Methods []string
+	Handler Handler
+	Host    string
+	Pattern string
+	Name    string
+
+	segments []serveMuxSegment
+	// routes of the same factory that take precedence over this one when both match a request
+	moreSpecific []*ServeMuxRoute
+}
+
+func (route *ServeMuxRoute) Match(request *http.Request) (RouteMatch, error) {
+	var routeMatch RouteMatch
+
+	params, ok := route.matchPath(request)
+	if !ok {
+		return routeMatch, ErrPathMismatch
+	}
+
+	for _, moreSpecificRoute := range route.moreSpecific {
+		if _, ok := moreSpecificRoute.matchPath(request); ok && moreSpecificRoute.allowsMethod(request.Method) {
+			return routeMatch, ErrPathMismatch
+		}
+	}
+
+	if !route.allowsMethod(request.Method) {
+		return routeMatch, ErrMethodNotAllowed
+	}
+
+	routeMatch.Handler = route.Handler
+	routeMatch.Params = params
+	routeMatch.RouteName = route.Name
+
+	return routeMatch, nil
+}
+
+func (route *ServeMuxRoute) allowsMethod(method string) bool {
+	if len(route.Methods) == 0 {
+		return true
+	}
+
+	if method == http.MethodHead && contains(route.Methods, http.MethodGet) {
+		return true
+	}
+
+	return contains(route.Methods, method)
+}
+
+func (route *ServeMuxRoute) matchPath(request *http.Request) (RouteParams, bool) {
+	if route.Host != "" && route.Host != requestHost(request) {
+		return nil, false
+	}
+
+	// like net/http.ServeMux, segments are split on the escaped path, so an encoded slash stays within its segment
+	pathSegments := strings.Split(strings.TrimPrefix(request.URL.EscapedPath(), "/"), "/")
+	for idx, pathSegment := range pathSegments {
+		if segment, err := url.PathUnescape(pathSegment); err == nil {
+			pathSegments[idx] = segment
+		}
+	}
+	params := make(RouteParams)
+
+	for idx, segment := range route.segments {
+		if idx >= len(pathSegments) {
+			return nil, false
+		}
+
+		switch segment.kind {
+		case serveMuxLiteral:
+			if pathSegments[idx] != segment.value {
+				return nil, false
+			}
+		case serveMuxWildcard:
+			if pathSegments[idx] == "" {
+				return nil, false
+			}
+
+			params[segment.value] = pathSegments[idx]
+		case serveMuxMultiWildcard:
+			if segment.value != "" {
+				params[segment.value] = strings.Join(pathSegments[idx:], "/")
+			}
+
+			return params, true
+		}
+	}
+
+	if len(pathSegments) != len(route.segments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+// isMoreSpecific reports whether the route takes precedence over the other one according to net/http.ServeMux rules:
+// a pattern with a host wins over a pattern without it, otherwise a pattern wins if it matches a subset of the other's
+// paths with a subset of its methods, and a strict subset of either of them.
+func (route *ServeMuxRoute) isMoreSpecific(other *ServeMuxRoute) bool {
+	if (route.Host != "") != (other.Host != "") {
+		return route.Host != ""
+	}
+
+	routeInOther := serveMuxSubset(route.segments, other.segments) && route.methodsSubset(other)
+	otherInRoute := serveMuxSubset(other.segments, route.segments) && other.methodsSubset(route)
+
+	return routeInOther && !otherInRoute
+}
+
+// methodsSubset reports whether every method the route allows is allowed by the other route as well.
+func (route *ServeMuxRoute) methodsSubset(other *ServeMuxRoute) bool {
+	if len(other.Methods) == 0 {
+		return true
+	}
+
+	if len(route.Methods) == 0 {
+		return false
+	}
+
+	for _, method := range route.Methods {
+		if !other.allowsMethod(method) {
+			return false
+		}
+	}
+
+	// a route allowing GET allows HEAD as well
+	return !contains(route.Methods, http.MethodGet) || other.allowsMethod(http.MethodHead)
+}
+
+// overlaps reports whether a request may match both routes, like net/http.ServeMux two such patterns conflict
+// unless one of them is more specific.
+func (route *ServeMuxRoute) overlaps(other *ServeMuxRoute) bool {
+	if route.Host != other.Host {
+		return false
+	}
+
+	methodsOverlap := len(route.Methods) == 0 || len(other.Methods) == 0
+	for _, method := range route.Methods {
+		methodsOverlap = methodsOverlap || other.allowsMethod(method)
+	}
+
+	for _, method := range other.Methods {
+		methodsOverlap = methodsOverlap || route.allowsMethod(method)
+	}
+
+	return methodsOverlap && serveMuxOverlap(route.segments, other.segments)
+}
+
+// serveMuxOverlap reports whether a path is matched by both segments a and segments b.
+func serveMuxOverlap(a, b []serveMuxSegment) bool {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		segmentA, segmentB := a[idx], b[idx]
+
+		switch {
+		case segmentA.kind == serveMuxMultiWildcard || segmentB.kind == serveMuxMultiWildcard:
+			return true
+		case segmentA.kind == serveMuxLiteral && segmentB.kind == serveMuxLiteral:
+			if segmentA.value != segmentB.value {
+				return false
+			}
+		case segmentA.kind == serveMuxLiteral && segmentA.value == "",
+			segmentB.kind == serveMuxLiteral && segmentB.value == "":
+			// a wildcard does not match an empty segment
+			return false
+		}
+	}
+
+	return len(a) == len(b)
+}
+
+// serveMuxSubset reports whether every path matched by segments a is matched by segments b as well.
+func serveMuxSubset(a, b []serveMuxSegment) bool {
+	for idx, segmentB := range b {
+		if idx >= len(a) {
+			return false
+		}
+
+		segmentA := a[idx]
+
+		switch segmentB.kind {
+		case serveMuxMultiWildcard:
+			return true
+		case serveMuxWildcard:
+			if segmentA.kind == serveMuxMultiWildcard || (segmentA.kind == serveMuxLiteral && segmentA.value == "") {
+				return false
+			}
+		case serveMuxLiteral:
+			if segmentA.kind != serveMuxLiteral || segmentA.value != segmentB.value {
+				return false
+			}
+		}
+	}
+
+	return len(a) == len(b)
+}
+
+func requestHost(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.Host)
+	if err != nil {
+		return request.Host
+	}
+
+	return host
+}
@@ -0,0 +1,114 @@
+package httprouter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type serveMuxRouteFactory struct {
+	routes []*ServeMuxRoute
+}
+
+// NewServeMuxRouteFactory creates a factory for net/http.ServeMux style patterns: "[METHOD ][HOST]/[PATH]",
+// where PATH may contain {name} and {name...} wildcards, end with {$} or with a trailing slash.
+// Like the literal factory it handles every path, so other factories must be passed to New before it.
+func NewServeMuxRouteFactory() *serveMuxRouteFactory { //nolint:golint,revive
+	return &serveMuxRouteFactory{}
+}
+
+func (f *serveMuxRouteFactory) Name() string {
+	return "servemux"
+}
+
+func (f *serveMuxRouteFactory) Handles(_ string) bool {
+	return true
+}
+
+func (f *serveMuxRouteFactory) CreateRoute(path string, methods []string, handler Handler, routeName string) Route {
+	method, host, segments := parseServeMuxPattern(path)
+
+	if method != "" {
+		methods = []string{method}
+	}
+
+	if routeName == "" {
+		routeName = path
+	}
+
+	route := &ServeMuxRoute{
+		Methods:  methods,
+		Handler:  handler,
+		Host:     host,
+		Pattern:  path,
+		Name:     routeName,
+		segments: segments,
+	}
+
+	for _, existingRoute := range f.routes {
+		if existingRoute.isMoreSpecific(route) {
+			route.moreSpecific = append(route.moreSpecific, existingRoute)
+		} else if route.isMoreSpecific(existingRoute) {
+			existingRoute.moreSpecific = append(existingRoute.moreSpecific, route)
+		} else if route.overlaps(existingRoute) {
+			panic(fmt.Sprintf("httprouter: pattern %q conflicts with pattern %q", path, existingRoute.Pattern))
+		}
+	}
+
+	f.routes = append(f.routes, route)
+
+	return route
+}
+
+func parseServeMuxPattern(pattern string) (string, string, []serveMuxSegment) {
+	var method string
+
+	rest := strings.TrimLeft(pattern, " \t")
+	if idx := strings.IndexAny(rest, " \t"); idx >= 0 && !strings.Contains(rest[:idx], "/") {
+		method = rest[:idx]
+		rest = strings.TrimLeft(rest[idx:], " \t")
+	}
+
+	slashIdx := strings.Index(rest, "/")
+	if slashIdx < 0 {
+		panic(fmt.Sprintf("httprouter: pattern %q has no path", pattern))
+	}
+
+	host := rest[:slashIdx]
+	pathSegments := strings.Split(rest[slashIdx+1:], "/")
+	segments := make([]serveMuxSegment, 0, len(pathSegments))
+
+	for idx, pathSegment := range pathSegments {
+		isLast := idx == len(pathSegments)-1
+
+		switch {
+		case pathSegment == "" && isLast:
+			segments = append(segments, serveMuxSegment{kind: serveMuxMultiWildcard})
+		case pathSegment == "{$}":
+			if !isLast {
+				panic(fmt.Sprintf("httprouter: {$} not at the end of pattern %q", pattern))
+			}
+
+			segments = append(segments, serveMuxSegment{kind: serveMuxLiteral})
+		case strings.HasPrefix(pathSegment, "{") && strings.HasSuffix(pathSegment, "}"):
+			name := pathSegment[1 : len(pathSegment)-1]
+
+			if strings.HasSuffix(name, "...") {
+				if !isLast {
+					panic(fmt.Sprintf("httprouter: {%s} not at the end of pattern %q", name, pattern))
+				}
+
+				segments = append(segments, serveMuxSegment{kind: serveMuxMultiWildcard, value: strings.TrimSuffix(name, "...")})
+
+				continue
+			}
+
+			segments = append(segments, serveMuxSegment{kind: serveMuxWildcard, value: name})
+		case strings.ContainsAny(pathSegment, "{}"):
+			panic(fmt.Sprintf("httprouter: wildcard must be a full segment in pattern %q", pattern))
+		default:
+			segments = append(segments, serveMuxSegment{kind: serveMuxLiteral, value: pathSegment})
+		}
+	}
+
+	return method, host, segments
+}
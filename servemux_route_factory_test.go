@@ -0,0 +1,117 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxRouteFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := httprouter.NewServeMuxRouteFactory()
+	assert.Equal(t, "servemux", factory.Name())
+
+	testCases := []struct {
+		path            string
+		shouldHandle    bool
+		expectedMethods []string
+		expectedHost    string
+	}{
+		{path: "/items/{id}", shouldHandle: true},
+		{path: "/files/{path...}", shouldHandle: true},
+		{path: "/{$}", shouldHandle: true},
+		{path: "/static/", shouldHandle: true},
+		{path: "GET /items/{id}", shouldHandle: true, expectedMethods: []string{http.MethodGet}},
+		{path: "example.com/items", shouldHandle: true, expectedHost: "example.com"},
+		{path: "POST example.com/items/{id}", shouldHandle: true, expectedMethods: []string{http.MethodPost}, expectedHost: "example.com"},
+		{path: "/items", shouldHandle: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.shouldHandle, factory.Handles(testCase.path))
+
+			if testCase.shouldHandle {
+				route := httprouter.NewServeMuxRouteFactory().CreateRoute(testCase.path, nil, &mockHandler{}, "")
+
+				serveMuxRoute, ok := route.(*httprouter.ServeMuxRoute)
+				if assert.True(t, ok, "Expected route to be of type *ServeMuxRoute") {
+					assert.Equal(t, testCase.expectedMethods, serveMuxRoute.Methods)
+					assert.Equal(t, testCase.expectedHost, serveMuxRoute.Host)
+					assert.Equal(t, testCase.path, serveMuxRoute.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestServeMuxRouteFactory_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	testCases := []string{
+		"GET",
+		"/items/{id...}/edit",
+		"/items/{$}/edit",
+		"/items/id-{id}",
+	}
+
+	for _, pattern := range testCases {
+		pattern := pattern
+
+		t.Run(pattern, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Panics(t, func() {
+				httprouter.NewServeMuxRouteFactory().CreateRoute(pattern, nil, &mockHandler{}, "")
+			})
+		})
+	}
+}
+
+func TestServeMuxRouteFactory_ConflictingPatterns(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		patterns       []string
+		shouldConflict bool
+	}{
+		{name: "overlapping wildcards", patterns: []string{"GET /a/{x}", "GET /{y}/b"}, shouldConflict: true},
+		{name: "overlap with a pattern without method", patterns: []string{"/a/{x}", "GET /{y}/b"}, shouldConflict: true},
+		{name: "more specific path with more methods", patterns: []string{"GET /a/{x}", "/a/b"}, shouldConflict: true},
+		{name: "same pattern twice", patterns: []string{"GET /items/{id}", "GET /items/{key}"}, shouldConflict: true},
+		{name: "different methods", patterns: []string{"GET /a/{x}", "POST /{y}/b"}},
+		{name: "different hosts", patterns: []string{"a.example.com/a/{x}", "b.example.com/{y}/b"}},
+		{name: "disjoint paths", patterns: []string{"/a/{x}", "/{y}/b/c"}},
+		{name: "wildcard and end of path", patterns: []string{"/a/{$}", "/{y}/{z}"}},
+		{name: "more specific pattern", patterns: []string{"/a/{x}", "/{y}/{z}"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			createRoutes := func() {
+				factory := httprouter.NewServeMuxRouteFactory()
+				for _, pattern := range testCase.patterns {
+					factory.CreateRoute(pattern, nil, &mockHandler{}, "")
+				}
+			}
+
+			if testCase.shouldConflict {
+				assert.Panics(t, createRoutes)
+			} else {
+				assert.NotPanics(t, createRoutes)
+			}
+		})
+	}
+}
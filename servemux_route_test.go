@@ -0,0 +1,150 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:funlen
+func TestServeMuxRoute_Precedence(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New(httprouter.NewServeMuxRouteFactory())
+
+	// registered from the least to the most specific to make sure precedence does not depend on the order
+	router.Any("/", nil, &mockHandler{}, "root")
+	router.Any("/items/", nil, &mockHandler{}, "items-subtree")
+	router.Any("/items/{id}", nil, &mockHandler{}, "item")
+	router.Any("GET /items/{id}", nil, &mockHandler{}, "get-item")
+	router.Any("GET /items/new", nil, &mockHandler{}, "new-item")
+	router.Any("/items/{$}", nil, &mockHandler{}, "items-exact")
+	router.Any("/files/{path...}", nil, &mockHandler{}, "files")
+	router.Any("api.example.com/items/{id}", nil, &mockHandler{}, "api-item")
+
+	testCases := []struct {
+		name              string
+		method            string
+		target            string
+		expectedRouteName string
+		expectedParams    httprouter.RouteParams
+	}{
+		{"Root", http.MethodGet, "/", "root", httprouter.RouteParams{}},
+		{"RootCatchAll", http.MethodGet, "/unknown/path", "root", httprouter.RouteParams{}},
+		{"ExactTrailingSlash", http.MethodGet, "/items/", "items-exact", httprouter.RouteParams{}},
+		{"Subtree", http.MethodGet, "/items/1/edit", "items-subtree", httprouter.RouteParams{}},
+		{"MethodWins", http.MethodGet, "/items/1", "get-item", httprouter.RouteParams{"id": "1"}},
+		{"HeadMatchesGet", http.MethodHead, "/items/1", "get-item", httprouter.RouteParams{"id": "1"}},
+		{"AnyMethodFallback", http.MethodDelete, "/items/1", "item", httprouter.RouteParams{"id": "1"}},
+		{"LiteralWins", http.MethodGet, "/items/new", "new-item", httprouter.RouteParams{}},
+		{"MultiWildcard", http.MethodGet, "/files/a/b/c.txt", "files", httprouter.RouteParams{"path": "a/b/c.txt"}},
+		{"MultiWildcardEmpty", http.MethodGet, "/files/", "files", httprouter.RouteParams{"path": ""}},
+		{"HostWins", http.MethodGet, "http://api.example.com:8080/items/1", "api-item", httprouter.RouteParams{"id": "1"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(testCase.method, testCase.target, nil)
+
+			routeMatch, err := router.Match(request)
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expectedRouteName, routeMatch.RouteName)
+				assert.Equal(t, testCase.expectedParams, routeMatch.Params)
+			}
+		})
+	}
+}
+
+func TestServeMuxRoute_WithPrefix(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New(httprouter.NewServeMuxRouteFactory())
+
+	router.Group(func(group httprouter.Router) {
+		group.WithPrefix("api")
+		group.Any("GET /items/{id}", nil, &mockHandler{}, "")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/items/1", nil)
+
+	routeMatch, err := router.Match(request)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GET /api/items/{id}", routeMatch.RouteName)
+	}
+}
+
+func TestServeMuxRoute_MethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	router := httprouter.New(httprouter.NewServeMuxRouteFactory())
+
+	router.Any("POST /orders/{id}", nil, &mockHandler{}, "")
+	router.Any("PUT /orders/{id}", nil, &mockHandler{}, "")
+
+	request := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+
+	_, err := router.Match(request)
+	assert.ErrorIs(t, err, httprouter.ErrMethodNotAllowed)
+
+	request = httptest.NewRequest(http.MethodPut, "/orders/1", nil)
+
+	routeMatch, err := router.Match(request)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "PUT /orders/{id}", routeMatch.RouteName)
+	}
+}
+
+func TestServeMuxRoute_EncodedSlash(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		useRawPath         bool
+		encodedSlashPolicy httprouter.EncodedSlashPolicy
+		target             string
+		expectedID         string
+	}{
+		{name: "decoded path", target: "/items/a%2Fb", expectedID: "a/b"},
+		{name: "escaped non-ASCII", target: "/items/caf%C3%A9", expectedID: "café"},
+		{name: "UseRawPath", useRawPath: true, target: "/items/a%2Fb", expectedID: "a/b"},
+		{
+			name:               "UseRawPath keeping encoded slashes",
+			useRawPath:         true,
+			encodedSlashPolicy: httprouter.EncodedSlashKeep,
+			target:             "/items/a%2Fb%20c",
+			expectedID:         "a%2Fb c",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var id string
+
+			router := httprouter.New(httprouter.NewServeMuxRouteFactory())
+			router.UseRawPath = testCase.useRawPath
+			router.EncodedSlashPolicy = testCase.encodedSlashPolicy
+			router.Get("/items/{id}", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				id = request.PathValue("id")
+
+				return nil
+			}), "")
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, testCase.target, nil))
+
+			assert.Equal(t, http.StatusOK, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedID, id)
+		})
+	}
+}
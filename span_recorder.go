@@ -0,0 +1,139 @@
+package httprouter
+
+import (
+	"context"
+	"crypto/rand"
+	"maps"
+	"sync"
+	"time"
+)
+
+// RecordedSpan is a span ended by a SpanRecorder tracer.
+type RecordedSpan struct {
+	Name        string
+	SpanContext SpanContext
+	// Parent is invalid for the root span of a trace.
+	Parent     SpanContext
+	Attributes map[string]any
+	StatusCode int
+	Err        error
+	StartTime  time.Time
+	EndTime    time.Time
+}
+
+// SpanRecorder is a Tracer that keeps ended spans in memory, it is meant for tests.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+func (r *SpanRecorder) Start(ctx context.Context, name string, parent SpanContext) (context.Context, Span) { //nolint:ireturn
+	spanContext := SpanContext{Flags: traceFlagSampled}
+	if parent.IsValid() {
+		spanContext.TraceID = parent.TraceID
+		spanContext.Flags = parent.Flags
+		spanContext.TraceState = parent.TraceState
+	} else {
+		_, _ = rand.Read(spanContext.TraceID[:])
+	}
+
+	_, _ = rand.Read(spanContext.SpanID[:])
+
+	span := &recordingSpan{
+		recorder: r,
+		span: RecordedSpan{
+			Name:        name,
+			SpanContext: spanContext,
+			Parent:      parent,
+			Attributes:  make(map[string]any),
+			StartTime:   time.Now(),
+		},
+	}
+
+	return ctx, span
+}
+
+// Spans returns the ended spans in the order they ended.
+func (r *SpanRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := make([]RecordedSpan, len(r.spans))
+	copy(spans, r.spans)
+
+	return spans
+}
+
+func (r *SpanRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = nil
+}
+
+type recordingSpan struct {
+	recorder *SpanRecorder
+
+	mu    sync.Mutex
+	span  RecordedSpan
+	ended bool
+}
+
+func (s *recordingSpan) SpanContext() SpanContext {
+	return s.span.SpanContext
+}
+
+func (s *recordingSpan) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.span.Name = name
+}
+
+func (s *recordingSpan) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.span.Attributes[key] = value
+}
+
+func (s *recordingSpan) SetStatus(statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.span.StatusCode = statusCode
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.span.Err = err
+}
+
+// End records the span, calls after the first one are ignored.
+func (s *recordingSpan) End() {
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.ended = true
+	s.span.EndTime = time.Now()
+	span := s.span
+	span.Attributes = maps.Clone(s.span.Attributes)
+
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans = append(s.recorder.spans, span)
+}
@@ -0,0 +1,143 @@
+package httprouter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type TimeoutConfig struct {
+	// Timeout is the time the next handler has to return, it is required.
+	Timeout time.Duration
+	// StatusCode answers timed out requests, 503 Service Unavailable is used when it is zero,
+	// set it to 504 Gateway Timeout for handlers that wait for upstream services.
+	StatusCode int
+}
+
+// Timeout sets a deadline on the request context and returns an HTTPError with http.ErrHandlerTimeout and StatusCode
+// to the router error handler if the next handler does not return in time. The handler keeps running until it returns,
+// it should stop once ctx.Err() is not nil. Its response is buffered and written only if it returns in time,
+// so it can't race with the error response; later writes fail with http.ErrHandlerTimeout. Buffering makes Timeout
+// unsuitable for streaming responses. Apply it to a group with Use or to a single route with With.
+func Timeout(config TimeoutConfig) MiddlewareFunc {
+	if config.Timeout <= 0 {
+		panic("httprouter: timeout must be positive")
+	}
+
+	if config.StatusCode == 0 {
+		config.StatusCode = http.StatusServiceUnavailable
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			ctx, cancel := context.WithTimeout(request.Context(), config.Timeout)
+			defer cancel()
+
+			// the handler may outlive the request, it gets its own route state which is kept only if it returns
+			// or panics in time
+			ctx, keepRouteState := isolateRouteState(ctx)
+
+			writer := &timeoutWriter{header: make(http.Header)}
+			done := make(chan error, 1)
+			panicked := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if value := recover(); value != nil {
+						panicked <- value
+					}
+				}()
+
+				done <- next.Handle(writer, request.WithContext(ctx))
+			}()
+
+			select {
+			case value := <-panicked:
+				keepRouteState()
+				panic(value)
+			case err := <-done:
+				keepRouteState()
+				writer.writeTo(responseWriter)
+
+				return err //nolint:wrapcheck
+			case <-ctx.Done():
+				writer.timeOut()
+
+				return NewHTTPError(config.StatusCode, http.ErrHandlerTimeout)
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers the response of a handler run by Timeout.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if !w.wroteHeader {
+		w.writeHeader(http.StatusOK)
+	}
+
+	return w.body.Write(data) //nolint:wrapcheck
+}
+
+func (w *timeoutWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut || w.wroteHeader {
+		return
+	}
+
+	w.writeHeader(statusCode)
+}
+
+func (w *timeoutWriter) writeHeader(statusCode int) {
+	w.status = statusCode
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) timeOut() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.timedOut = true
+}
+
+// writeTo copies the buffered response, headers are copied even if nothing was written,
+// so they are part of the error response to an error the handler returned.
+func (w *timeoutWriter) writeTo(responseWriter http.ResponseWriter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	header := responseWriter.Header()
+	for name, values := range w.header {
+		header[name] = values
+	}
+
+	if !w.wroteHeader {
+		return
+	}
+
+	responseWriter.WriteHeader(w.status)
+	_, _ = responseWriter.Write(w.body.Bytes())
+}
@@ -0,0 +1,202 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		config         httprouter.TimeoutConfig
+		handler        httprouter.Handler
+		expectedStatus int
+		expectedBody   string
+		expectedHeader string
+	}{
+		{
+			name:   "handler returns in time",
+			config: httprouter.TimeoutConfig{Timeout: time.Second},
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("X-Handler", "fast")
+				responseWriter.WriteHeader(http.StatusCreated)
+				_, _ = responseWriter.Write([]byte("created"))
+
+				return nil
+			}),
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "created",
+			expectedHeader: "fast",
+		},
+		{
+			name:   "handler returns an error in time",
+			config: httprouter.TimeoutConfig{Timeout: time.Second},
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) error {
+				responseWriter.Header().Set("X-Handler", "failed")
+
+				return httprouter.NewHTTPError(http.StatusConflict, errors.New("conflict"))
+			}),
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "Conflict\n",
+			expectedHeader: "failed",
+		},
+		{
+			name:   "handler times out",
+			config: httprouter.TimeoutConfig{Timeout: 10 * time.Millisecond},
+			handler: httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+				responseWriter.Header().Set("X-Handler", "slow")
+				_, _ = responseWriter.Write([]byte("partial"))
+
+				<-request.Context().Done()
+
+				return request.Context().Err()
+			}),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "Service Unavailable\n",
+		},
+		{
+			name:   "handler times out with custom status",
+			config: httprouter.TimeoutConfig{Timeout: 10 * time.Millisecond, StatusCode: http.StatusGatewayTimeout},
+			handler: httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				<-request.Context().Done()
+
+				return request.Context().Err()
+			}),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "Gateway Timeout\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := httprouter.New()
+			router.With(httprouter.Timeout(testCase.config)).Get("/report", testCase.handler, "")
+
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+			assert.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			assert.Equal(t, testCase.expectedBody, responseRecorder.Body.String())
+			assert.Equal(t, testCase.expectedHeader, responseRecorder.Header().Get("X-Handler"))
+		})
+	}
+}
+
+func TestTimeout_HandlerObservesDeadline(t *testing.T) {
+	t.Parallel()
+
+	handlerErr := make(chan error, 1)
+	writeErr := make(chan error, 1)
+
+	var timeoutErr error
+
+	router := httprouter.New()
+	router.ErrorHandler = func(responseWriter http.ResponseWriter, request *http.Request, err error) {
+		timeoutErr = err
+
+		httprouter.DefaultErrorHandler(responseWriter, request, err)
+	}
+	router.With(httprouter.Timeout(httprouter.TimeoutConfig{Timeout: 10 * time.Millisecond})).Get("/report",
+		httprouter.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			<-request.Context().Done()
+			handlerErr <- request.Context().Err()
+
+			// give the middleware time to answer before writing
+			time.Sleep(10 * time.Millisecond)
+
+			_, err := responseWriter.Write([]byte("late"))
+			writeErr <- err
+
+			return nil
+		}), "")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	assert.ErrorIs(t, <-handlerErr, context.DeadlineExceeded)
+	assert.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+	assert.ErrorIs(t, timeoutErr, http.ErrHandlerTimeout)
+	assert.Equal(t, "Service Unavailable\n", responseRecorder.Body.String())
+}
+
+func TestTimeout_RouteState(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handlerDone := make(chan struct{})
+
+	router := httprouter.New()
+	router.Pre(httprouter.AccessLog(httprouter.AccessLogConfig{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}))
+	router.Use(
+		httprouter.Timeout(httprouter.TimeoutConfig{Timeout: 10 * time.Millisecond}),
+		httprouter.RequestID(httprouter.RequestIDConfig{}),
+		httprouter.Metrics(httprouter.MetricsConfig{Sink: &recordingSink{}}),
+	)
+	router.Get("/fast", &mockHandler{}, "")
+	// the handler keeps running the inner middlewares after the timeout while AccessLog reads the route state
+	router.Get("/slow", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+		defer close(handlerDone)
+
+		<-request.Context().Done()
+
+		return request.Context().Err() //nolint:wrapcheck
+	}), "")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, responseRecorder.Header().Get(httprouter.DefaultRequestIDHeader), record["request_id"],
+		"the state of a handler that returned in time is kept")
+
+	responseRecorder = httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	<-handlerDone
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+}
+
+func TestTimeout_Panic(t *testing.T) {
+	t.Parallel()
+
+	var panicErr *httprouter.PanicError
+
+	router := httprouter.New()
+	router.Pre(httprouter.Recover(httprouter.RecoverConfig{
+		OnPanic: func(_ *http.Request, err *httprouter.PanicError) {
+			panicErr = err
+		},
+	}))
+	router.With(httprouter.Timeout(httprouter.TimeoutConfig{Timeout: time.Second})).Get("/report",
+		httprouter.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+			panic("boom")
+		}), "report")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, "report", panicErr.Route.Name)
+}
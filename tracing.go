@@ -0,0 +1,220 @@
+package httprouter
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	traceparentHeader = "Traceparent"
+	tracestateHeader  = "Tracestate"
+
+	traceFlagSampled byte = 0x01
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is the W3C Trace Context of a span: the traceparent fields and the opaque tracestate.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+}
+
+// IsValid reports whether both the trace id and the span id are not all zeros.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&traceFlagSampled != 0
+}
+
+// Traceparent formats the span context as a version 00 traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses traceparent and tracestate header values, it returns ErrInvalidTraceparent
+// if the traceparent does not follow the W3C Trace Context format.
+func ParseTraceparent(traceparent string, tracestate string) (SpanContext, error) {
+	var spanContext SpanContext
+
+	// version 00 is exactly 55 characters long, later versions may append fields after a dash
+	if len(traceparent) < 55 || (len(traceparent) > 55 && traceparent[55] != '-') {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	fields := strings.SplitN(traceparent[:55], "-", 4)
+	if len(fields) != 4 || !isLowerHex(traceparent[:55]) {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	version, traceID, spanID, flags := fields[0], fields[1], fields[2], fields[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(traceparent) != 55) {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	_, _ = hex.Decode(spanContext.TraceID[:], []byte(traceID))
+	_, _ = hex.Decode(spanContext.SpanID[:], []byte(spanID))
+
+	var flagsByte [1]byte
+	_, _ = hex.Decode(flagsByte[:], []byte(flags))
+	spanContext.Flags = flagsByte[0]
+
+	if !spanContext.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	spanContext.TraceState = strings.TrimSpace(tracestate)
+
+	return spanContext, nil
+}
+
+func isLowerHex(value string) bool {
+	for _, char := range value {
+		if char != '-' && (char < '0' || char > '9') && (char < 'a' || char > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tracer starts spans, implement it to plug in a tracing library, e.g. OpenTelemetry.
+type Tracer interface {
+	// Start starts a span that continues the remote parent, the parent is invalid when the request
+	// had no valid traceparent header and a new trace should be started.
+	Start(ctx context.Context, name string, parent SpanContext) (context.Context, Span)
+}
+
+type Span interface {
+	SpanContext() SpanContext
+	SetName(name string)
+	SetAttribute(key string, value any)
+	SetStatus(statusCode int)
+	RecordError(err error)
+	End()
+}
+
+type TracingConfig struct {
+	// Tracer starts a span per request, it is required, see SpanRecorder.
+	Tracer Tracer
+}
+
+// Tracing starts a span per request that continues the trace of the incoming traceparent and tracestate headers.
+// The span is named after the method and the matched route pattern, and records the status and the error returned
+// by the next handler. The status of a request whose handler returned an error without writing a response is the one
+// DefaultErrorHandler answers with. A panic of the next handler ends the span with status 500 and a PanicError
+// before it is panicked again, add Recover before Tracing to answer it. Add it with Router.Pre to trace unmatched
+// requests as well.
+func Tracing(config TracingConfig) MiddlewareFunc {
+	if config.Tracer == nil {
+		panic("httprouter: tracer is nil")
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) error {
+			parent, _ := ParseTraceparent(
+				request.Header.Get(traceparentHeader),
+				strings.Join(request.Header.Values(tracestateHeader), ","),
+			)
+
+			ctx, span := config.Tracer.Start(request.Context(), request.Method, parent)
+			ctx = context.WithValue(ctx, spanKey, span)
+
+			span.SetAttribute("http.request.method", request.Method)
+			span.SetAttribute("url.path", request.URL.Path)
+
+			// the span ends even if the next handler panics, the panic is recorded and passed on to Recover
+			defer func() {
+				value := recover()
+
+				if routeInfo := CurrentRoute(ctx); routeInfo.Pattern != "" {
+					span.SetName(request.Method + " " + routeInfo.Pattern)
+					span.SetAttribute("http.route", routeInfo.Pattern)
+				}
+
+				if value != nil {
+					span.SetStatus(http.StatusInternalServerError)
+					span.RecordError(&PanicError{
+						Value:     value,
+						Route:     CurrentRoute(ctx),
+						RequestID: RequestIDFromContext(ctx),
+					})
+				}
+
+				span.End()
+
+				if value != nil {
+					panic(value)
+				}
+			}()
+
+			recorder := newResponseRecorder(responseWriter)
+
+			err := next.Handle(recorder, request.WithContext(ctx))
+
+			span.SetStatus(recorder.statusFor(err))
+
+			if err != nil {
+				span.RecordError(err)
+			}
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+// SpanFromContext returns the span started by the Tracing middleware,
+// a span that records nothing is returned if there is none.
+func SpanFromContext(ctx context.Context) Span { //nolint:ireturn
+	span, ok := ctx.Value(spanKey).(Span)
+	if !ok {
+		return noopSpan{}
+	}
+
+	return span
+}
+
+// InjectTraceContext sets the traceparent and tracestate headers of an outgoing request,
+// so the trace continues in the service it is sent to.
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	spanContext := SpanFromContext(ctx).SpanContext()
+	if !spanContext.IsValid() {
+		return
+	}
+
+	header.Set(traceparentHeader, spanContext.Traceparent())
+
+	if spanContext.TraceState != "" {
+		header.Set(tracestateHeader, spanContext.TraceState)
+	}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext { return SpanContext{} }
+func (noopSpan) SetName(string)           {}
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) SetStatus(int)            {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
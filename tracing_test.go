@@ -0,0 +1,258 @@
+package httprouter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inbugay1/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		traceparent         string
+		tracestate          string
+		expectedErr         error
+		expectedTraceparent string
+		expectedSampled     bool
+	}{
+		{
+			name:                "sampled",
+			traceparent:         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			tracestate:          "congo=t61rcWkgMzE",
+			expectedTraceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedSampled:     true,
+		},
+		{
+			name:                "not sampled",
+			traceparent:         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expectedTraceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			name:                "future version with extra fields",
+			traceparent:         "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-holds",
+			expectedTraceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedSampled:     true,
+		},
+		{
+			name:        "empty",
+			traceparent: "",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "version 00 with extra fields",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "forbidden version",
+			traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "uppercase hex",
+			traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "zero trace id",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "zero span id",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+		{
+			name:        "misplaced dash",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e47-3600f067aa0ba902b7-01",
+			expectedErr: httprouter.ErrInvalidTraceparent,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			spanContext, err := httprouter.ParseTraceparent(testCase.traceparent, testCase.tracestate)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+
+			if testCase.expectedErr != nil {
+				assert.False(t, spanContext.IsValid())
+
+				return
+			}
+
+			assert.Equal(t, testCase.expectedTraceparent, spanContext.Traceparent())
+			assert.Equal(t, testCase.expectedSampled, spanContext.IsSampled())
+			assert.Equal(t, testCase.tracestate, spanContext.TraceState)
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	errHandler := httprouter.NewHTTPError(http.StatusConflict, errors.New("conflict"))
+
+	testCases := []struct {
+		name               string
+		path               string
+		traceparent        string
+		expectedName       string
+		expectedStatusCode int
+		expectedErr        error
+		expectedAttributes map[string]any
+	}{
+		{
+			name:               "continues the incoming trace",
+			path:               "/users/42",
+			traceparent:        traceparent,
+			expectedName:       "GET /users/:id",
+			expectedStatusCode: http.StatusOK,
+			expectedAttributes: map[string]any{
+				"http.request.method": http.MethodGet,
+				"url.path":            "/users/42",
+				"http.route":          "/users/:id",
+			},
+		},
+		{
+			name:               "records the handler error",
+			path:               "/fail",
+			expectedName:       "GET /fail",
+			expectedStatusCode: http.StatusConflict,
+			expectedErr:        errHandler,
+			expectedAttributes: map[string]any{
+				"http.request.method": http.MethodGet,
+				"url.path":            "/fail",
+				"http.route":          "/fail",
+			},
+		},
+		{
+			name:               "unmatched route",
+			path:               "/missing",
+			traceparent:        "invalid",
+			expectedName:       http.MethodGet,
+			expectedStatusCode: http.StatusNotFound,
+			expectedAttributes: map[string]any{
+				"http.request.method": http.MethodGet,
+				"url.path":            "/missing",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httprouter.NewSpanRecorder()
+
+			var outgoingHeader http.Header
+
+			router := httprouter.New(httprouter.NewPlaceholderRouteFactory())
+			router.Pre(httprouter.Tracing(httprouter.TracingConfig{Tracer: recorder}))
+			router.Get("/users/:id", httprouter.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) error {
+				outgoingHeader = make(http.Header)
+				httprouter.InjectTraceContext(request.Context(), outgoingHeader)
+
+				return nil
+			}), "user")
+			router.Get("/fail", &mockHandler{errToReturn: errHandler}, "fail")
+
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			if testCase.traceparent != "" {
+				request.Header.Set("traceparent", testCase.traceparent)
+				request.Header.Add("tracestate", "congo=t61rcWkgMzE")
+				request.Header.Add("tracestate", "rojo=00f067aa0ba902b7")
+			}
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			spans := recorder.Spans()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+
+			assert.Equal(t, testCase.expectedName, span.Name)
+			assert.Equal(t, testCase.expectedStatusCode, span.StatusCode)
+			assert.Equal(t, testCase.expectedErr, span.Err)
+			assert.Equal(t, testCase.expectedAttributes, span.Attributes)
+			assert.True(t, span.SpanContext.IsValid())
+			assert.True(t, span.SpanContext.IsSampled())
+			assert.False(t, span.EndTime.Before(span.StartTime))
+
+			if testCase.traceparent != traceparent {
+				assert.False(t, span.Parent.IsValid())
+				assert.NotEqual(t, traceID, span.SpanContext.TraceID.String())
+
+				return
+			}
+
+			assert.Equal(t, traceparent, span.Parent.Traceparent())
+			assert.Equal(t, traceID, span.SpanContext.TraceID.String())
+			assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", span.SpanContext.TraceState)
+
+			assert.Equal(t, span.SpanContext.Traceparent(), outgoingHeader.Get("traceparent"))
+			assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", outgoingHeader.Get("tracestate"))
+		})
+	}
+}
+
+func TestTracing_Panic(t *testing.T) {
+	t.Parallel()
+
+	recorder := httprouter.NewSpanRecorder()
+
+	router := httprouter.New()
+	router.Pre(httprouter.Recover(httprouter.RecoverConfig{}), httprouter.Tracing(httprouter.TracingConfig{Tracer: recorder}))
+	router.Get("/panic", httprouter.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	}), "panic")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+
+	spans := recorder.Spans()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+
+	assert.Equal(t, "GET /panic", span.Name)
+	assert.Equal(t, http.StatusInternalServerError, span.StatusCode)
+
+	var panicErr *httprouter.PanicError
+
+	require.ErrorAs(t, span.Err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, "panic", panicErr.Route.Name)
+}
+
+func TestInjectTraceContext_WithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	header := make(http.Header)
+	httprouter.InjectTraceContext(context.Background(), header)
+
+	assert.Empty(t, header)
+	assert.False(t, httprouter.SpanFromContext(context.Background()).SpanContext().IsValid())
+}
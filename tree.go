@@ -77,6 +77,15 @@ func (tree *tree) Insert(path string, handler Handler) *node {
 }
 
 func (tree *tree) Search(path string) (Handler, map[string]string) {
+	currentNode, params := tree.search(path)
+	if currentNode == nil {
+		return nil, nil
+	}
+
+	return currentNode.Value, params
+}
+
+func (tree *tree) search(path string) (*node, map[string]string) {
 	currentNode := tree.Root
 	start := 0
 	params := make(map[string]string)
@@ -104,5 +113,5 @@ func (tree *tree) Search(path string) (Handler, map[string]string) {
 		}
 	}
 
-	return currentNode.Value, params
+	return currentNode, params
 }